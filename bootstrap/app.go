@@ -11,7 +11,7 @@ import (
 type Application struct {
 	Config         *Config
 	DB             *gorm.DB
-	CasbinEnforcer *casbin.Enforcer
+	CasbinEnforcer *casbin.SyncedEnforcer
 	Cryptos        cryptos.Cryptos
 	Validator      *validator.Validator
 	Mailer         mailer.Mailer
@@ -21,10 +21,11 @@ type AppFunc func(*Application)
 
 func defaultApp() Application {
 	myConfig := NewConfig()
+	db := NewDatabase(myConfig)
 	return Application{
 		Config:         myConfig,
-		DB:             NewDatabase(myConfig),
-		CasbinEnforcer: NewCasbinEnforcer(myConfig),
+		DB:             db,
+		CasbinEnforcer: NewCasbinEnforcer(myConfig, db),
 		Cryptos:        NewCryptos(myConfig),
 		Validator:      NewValidator(),
 	}
@@ -46,7 +47,7 @@ func App() Application {
 	app := &Application{}
 	app.Config = NewConfig()
 	app.DB = NewDatabase(app.Config)
-	app.CasbinEnforcer = NewCasbinEnforcer(app.Config)
+	app.CasbinEnforcer = NewCasbinEnforcer(app.Config, app.DB)
 	app.Cryptos = NewCryptos(app.Config)
 	app.Validator = NewValidator()
 
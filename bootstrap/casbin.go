@@ -3,25 +3,69 @@ package bootstrap
 import (
 	"log"
 	"sync"
+	"time"
 
 	"github.com/casbin/casbin"
+	"github.com/koropati/population-recap/internal/casbinadapter"
+	"gorm.io/gorm"
 )
 
+// defaultCasbinReloadInterval dipakai bila CASBIN_RELOAD_INTERVAL tidak diisi.
+const defaultCasbinReloadInterval = 10
+
 var (
 	casbinOnce     sync.Once
-	casbinEnforcer *casbin.Enforcer
+	casbinEnforcer *casbin.SyncedEnforcer
 )
 
-// NewRedisClient creates a new Redis client connection
-func NewCasbinEnforcer(config *Config) *casbin.Enforcer {
+// NewCasbinEnforcer memuat policy dari tabel casbin_rules setelah menambahkan
+// aturan CASBIN_POLICY_PATH yang belum pernah disalin. Enforcer memuat ulang
+// policy setiap kali tabel berubah, termasuk perubahan dari proses lain.
+func NewCasbinEnforcer(config *Config, db *gorm.DB) *casbin.SyncedEnforcer {
 	casbinOnce.Do(func() {
+		adapter := casbinadapter.NewAdapter(db)
+		if err := seedCasbinPolicy(config, adapter); err != nil {
+			log.Fatal(err)
+		}
 
-		authEnforcer, err := casbin.NewEnforcerSafe(config.CasbinModelPath, config.CasbinPolicyPath)
+		authEnforcer, err := casbin.NewSyncedEnforcerSafe(config.CasbinModelPath, adapter)
 		if err != nil {
 			log.Fatal(err)
 		}
+
+		interval := config.CasbinReloadInterval
+		if interval <= 0 {
+			interval = defaultCasbinReloadInterval
+		}
+		watcher, err := casbinadapter.NewWatcher(db, time.Duration(interval)*time.Second)
+		if err != nil {
+			log.Fatal(err)
+		}
+		authEnforcer.SetWatcher(watcher)
 		casbinEnforcer = authEnforcer
 	})
 
 	return casbinEnforcer
 }
+
+// seedCasbinPolicy menyalin aturan policy.csv yang belum pernah disalin ke
+// database, sehingga aturan baru ikut berlaku pada instalasi yang sudah
+// berjalan tanpa menimpa perubahan dari editor policy saat restart.
+func seedCasbinPolicy(config *Config, adapter *casbinadapter.Adapter) error {
+	if config.CasbinPolicyPath == "" {
+		return nil
+	}
+
+	fileEnforcer, err := casbin.NewEnforcerSafe(config.CasbinModelPath, config.CasbinPolicyPath)
+	if err != nil {
+		return err
+	}
+	added, err := adapter.SeedPolicy(fileEnforcer.GetModel())
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		log.Printf("Seeded %d casbin policies from %s\n", added, config.CasbinPolicyPath)
+	}
+	return nil
+}
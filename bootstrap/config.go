@@ -43,9 +43,14 @@ type Config struct {
 	SmtpEncryption              string `mapstructure:"SMTP_ENCRYPTION"`
 	CasbinModelPath             string `mapstructure:"CASBIN_MODEL_PATH"`
 	CasbinPolicyPath            string `mapstructure:"CASBIN_POLICY_PATH"`
+	CasbinReloadInterval        int    `mapstructure:"CASBIN_RELOAD_INTERVAL"`
 	SecretKey                   string `mapstructure:"SECRET_KEY"`
 	SessionKey                  string `mapstructure:"SESSION_KEY"`
 	TelegramBotToken            string `mapstructure:"TELEGRAM_BOT_TOKEN"`
+	ReportCity                  string `mapstructure:"REPORT_CITY"`
+	ReportSignerName            string `mapstructure:"REPORT_SIGNER_NAME"`
+	ReportSignerNIP             string `mapstructure:"REPORT_SIGNER_NIP"`
+	SoftDeleteRetentionDays     int    `mapstructure:"SOFT_DELETE_RETENTION_DAYS"`
 }
 
 func NewConfig() *Config {
@@ -2,10 +2,14 @@ package bootstrap
 
 import (
 	"fmt"
+	"log"
+	"net/url"
 
 	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/auditlog"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func NewDatabase(config *Config) *gorm.DB {
@@ -16,12 +20,23 @@ func NewDatabase(config *Config) *gorm.DB {
 	dbPass := config.DBPass
 	dbName := config.DBName
 
-	dbConnString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPass, dbHost, dbPort, dbName)
+	// parseTime diperlukan agar kolom DATE seperti tanggal lahir dapat dibaca
+	// sebagai time.Time, dalam zona waktu yang sama dengan usecase.
+	dbConnString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=true&loc=%s", dbUser, dbPass, dbHost, dbPort, dbName, url.QueryEscape(domain.TimeZone))
 	db, err := gorm.Open(mysql.Open(dbConnString), &gorm.Config{})
 	if err != nil {
 		panic("failed to connect database")
 	}
+	if err := db.Use(auditlog.New(domain.AuditableTables...)); err != nil {
+		panic("failed to register audit log")
+	}
 	AutoMigrate(db)
+	runDataMigration(db, "backfill_validity_periods", BackfillValidityPeriods)
+	runDataMigration(db, "backfill_email_verification", BackfillEmailVerification)
+	runDataMigration(db, "release_deleted_user_emails", ReleaseDeletedUserEmails)
+	runDataMigration(db, "drop_resident_duplicate_foreign_keys", DropResidentDuplicateForeignKeys)
+	runDataMigration(db, "drop_refresh_token_pair_token", DropRefreshTokenPairToken)
+	runDataMigration(db, "drop_single_sort_indexes", DropSingleSortIndexes)
 	return db
 }
 
@@ -35,8 +50,131 @@ func CloseDatabase(db *gorm.DB) {
 func AutoMigrate(db *gorm.DB) {
 	db.AutoMigrate(
 		&domain.User{},
+		&domain.UserRegion{},
 		&domain.AccessToken{},
 		&domain.RefreshToken{},
 		&domain.ForgotPasswordToken{},
+		&domain.VerificationEmailToken{},
+		&domain.Region{},
+		&domain.Resident{},
+		&domain.ResidencePeriod{},
+		&domain.Family{},
+		&domain.FamilyMember{},
+		&domain.Mutation{},
+		&domain.Migration{},
+		&domain.CertificateCounter{},
+		&domain.CasbinRule{},
+		&domain.CasbinSeed{},
+		&domain.AuditLog{},
+		&domain.ResidentDuplicate{},
+		&domain.DataMigration{},
 	)
 }
+
+// runDataMigration menjalankan perbaikan data bernama name satu kali saja.
+// Perbaikan dan catatannya disimpan dalam satu transaksi sehingga perbaikan
+// yang gagal dicatat di log dan diulang saat aplikasi dimulai berikutnya.
+func runDataMigration(db *gorm.DB, name string, migrate func(tx *gorm.DB) error) {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var applied int64
+		if err := tx.Table(domain.DataMigrationTable).Where("name = ?", name).Count(&applied).Error; err != nil {
+			return err
+		}
+		if applied > 0 {
+			return nil
+		}
+		if err := migrate(tx); err != nil {
+			return err
+		}
+		return tx.Table(domain.DataMigrationTable).Create(&domain.DataMigration{Name: name}).Error
+	})
+	if err != nil {
+		log.Printf("data migration %s failed: %v", name, err)
+	}
+}
+
+// BackfillValidityPeriods melengkapi masa tinggal dan masa keanggotaan KK
+// untuk data yang tercatat sebelum rekap per tanggal tersedia. Data lama
+// dianggap berlaku sejak lahir atau tanggal datang sampai meninggal atau
+// pindah ke luar.
+func BackfillValidityPeriods(tx *gorm.DB) error {
+	err := tx.Exec("INSERT INTO " + domain.ResidencePeriodTable + " (id, resident_id, region_id, valid_from, valid_until, created_at, updated_at) " +
+		"SELECT UUID(), p.id, p.region_id, COALESCE(p.arrival_date, p.birth_date), COALESCE(p.death_date, p.departure_date), UNIX_TIMESTAMP(), UNIX_TIMESTAMP() " +
+		"FROM " + domain.ResidentTable + " AS p " +
+		"WHERE NOT EXISTS (SELECT 1 FROM " + domain.ResidencePeriodTable + " AS rp WHERE rp.resident_id = p.id)").Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec("UPDATE " + domain.FamilyMemberTable + " AS m JOIN " + domain.ResidentTable + " AS p ON p.id = m.resident_id " +
+		"SET m.valid_from = COALESCE(p.arrival_date, p.birth_date) WHERE m.valid_from IS NULL").Error
+}
+
+// BackfillEmailVerification menganggap email pengguna yang sudah aktif
+// sebelum verifikasi email tersedia sebagai terverifikasi. Perbaikan ini hanya
+// dijalankan sekali karena sesudahnya super_admin dapat mengaktifkan akun yang
+// emailnya belum terverifikasi.
+func BackfillEmailVerification(tx *gorm.DB) error {
+	return tx.Exec("UPDATE " + domain.UserTable + " SET email_verified_at = UNIX_TIMESTAMP() WHERE is_active = 1 AND email_verified_at = 0").Error
+}
+
+// ReleaseDeletedUserEmails memberi akhiran pada email pengguna yang sudah
+// dihapus sebelum Delete melakukannya, sehingga email tersebut dapat dipakai
+// mendaftar lagi.
+func ReleaseDeletedUserEmails(tx *gorm.DB) error {
+	return tx.Exec("UPDATE "+domain.UserTable+" SET email = CONCAT(email, ?, id) WHERE deleted_at IS NOT NULL AND email NOT LIKE ?", domain.DeletedEmailSeparator, "%"+domain.DeletedEmailSeparator+"%").Error
+}
+
+// DropResidentDuplicateForeignKeys menghapus foreign key resident_duplicates
+// ke residents yang dibuat AutoMigrate sebelumnya, karena foreign key tersebut
+// menggagalkan Purge penduduk yang pernah digabung.
+func DropResidentDuplicateForeignKeys(tx *gorm.DB) error {
+	for _, name := range []string{"fk_resident_duplicates_resident", "fk_resident_duplicates_duplicate"} {
+		if !tx.Migrator().HasConstraint(&domain.ResidentDuplicate{}, name) {
+			continue
+		}
+		if err := tx.Exec("ALTER TABLE ? DROP FOREIGN KEY ?", clause.Table{Name: domain.ResidentDuplicateTable}, clause.Column{Name: name}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropRefreshTokenPairToken menghapus kolom pair_token lama yang menyimpan
+// access token utuh. Kolom tersebut terlalu pendek untuk JWT sehingga
+// pasangan token kini dicocokkan melalui pair_token_id.
+func DropRefreshTokenPairToken(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&domain.RefreshToken{}, "pair_token") {
+		return nil
+	}
+	return tx.Migrator().DropColumn(&domain.RefreshToken{}, "pair_token")
+}
+
+// DropSingleSortIndexes menghapus index satu kolom pada kolom urutan yang
+// kini digantikan index gabungan (kolom, id) milik pagination cursor.
+func DropSingleSortIndexes(tx *gorm.DB) error {
+	indexes := []struct {
+		model interface{}
+		name  string
+	}{
+		{&domain.Resident{}, "idx_residents_name"},
+		{&domain.Resident{}, "idx_residents_birth_date"},
+		{&domain.Mutation{}, "idx_mutations_type"},
+		{&domain.Mutation{}, "idx_mutations_event_date"},
+		{&domain.Migration{}, "idx_migrations_move_date"},
+		{&domain.ResidentDuplicate{}, "idx_resident_duplicates_score"},
+		{&domain.AuditLog{}, "idx_audit_logs_action"},
+		{&domain.AuditLog{}, "idx_audit_logs_created_at"},
+		{&domain.User{}, "idx_users_name"},
+		{&domain.User{}, "idx_users_role"},
+		{&domain.Region{}, "idx_regions_name"},
+	}
+	for _, index := range indexes {
+		if !tx.Migrator().HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := tx.Migrator().DropIndex(index.model, index.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
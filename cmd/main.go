@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -15,9 +16,13 @@ import (
 	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/spreadsheet"
 	"github.com/koropati/population-recap/middleware"
+	"github.com/koropati/population-recap/repository"
 	"github.com/koropati/population-recap/routes"
 	"github.com/koropati/population-recap/scheduler"
+	"github.com/koropati/population-recap/usecase"
 	"github.com/vektra/mockery/mockery"
 )
 
@@ -210,6 +215,68 @@ func parseConfigFromArgs(args []string) Config {
 	return config
 }
 
+// importResidents mengimpor berkas penduduk CSV/XLSX dari command line dan
+// menulis laporan kesalahan per baris bila -report diisi.
+func importResidents(args []string) {
+	var file, report string
+
+	flagSet := flag.NewFlagSet("import", flag.ExitOnError)
+	flagSet.StringVar(&file, "file", "", "path of the csv or xlsx file to import")
+	flagSet.StringVar(&report, "report", "", "path of the csv or xlsx error report to write")
+	flagSet.Parse(args)
+
+	if file == "" {
+		log.Fatalf("use -file to specify the csv or xlsx file to import\n")
+	}
+	format, err := spreadsheet.FormatFromFilename(file)
+	if err != nil {
+		log.Fatalf("%s: %v\n", file, err)
+	}
+	var reportFormat string
+	if report != "" {
+		if reportFormat, err = spreadsheet.FormatFromFilename(report); err != nil {
+			log.Fatalf("%s: %v\n", report, err)
+		}
+	}
+
+	input, err := os.Open(file)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v\n", file, err)
+	}
+	defer input.Close()
+
+	app := bootstrap.NewApp()
+	timeout := time.Duration(app.Config.ContextTimeout) * time.Second
+	defer app.CloseDBConnection()
+
+	ir := repository.NewResidentImportRepository(app.DB)
+	gr := repository.NewRegionRepository(app.DB, domain.RegionTable, app.Config.DefaultPageNumber, app.Config.DefaultPageSize)
+	iu := usecase.NewResidentImportUsecase(ir, gr, app.Validator, timeout)
+
+	result, err := iu.Import(context.Background(), format, input)
+	if err != nil {
+		log.Fatalf("failed to import %s: %v\n", file, err)
+	}
+	log.Printf("Rows: %d, created: %d, updated: %d, failed: %d\n", result.TotalRows, result.Created, result.Updated, result.Failed)
+
+	if report == "" {
+		for _, rowError := range result.Errors {
+			log.Printf("row %d (%s): %s\n", rowError.Row, rowError.NIK, rowError.Message)
+		}
+		return
+	}
+
+	output, err := os.Create(report)
+	if err != nil {
+		log.Fatalf("failed to create %s: %v\n", report, err)
+	}
+	defer output.Close()
+	if err := iu.WriteReport(reportFormat, result, output); err != nil {
+		log.Fatalf("failed to write %s: %v\n", report, err)
+	}
+	log.Printf("Error report written to %s\n", report)
+}
+
 func handleCommand() {
 	if len(os.Args) >= 2 {
 		switch command := os.Args[1]; command {
@@ -260,11 +327,15 @@ func handleCommand() {
 
 			scheduler.InitCron(&cronConfig)
 
+		case "import":
+			importResidents(os.Args[2:])
+
 		case "help":
 			log.Printf("Available List Command:\n")
 			log.Printf("- go run cmd\\main.go server    (to start server process)\n")
 			log.Printf("- go run cmd\\main.go consumer (to start scheduler consumer process)\n")
 			log.Printf("- go run cmd\\main.go publisher (to start scheduler publisher process)\n")
+			log.Printf("- go run cmd\\main.go import    (to import residents, -file data.xlsx [-report errors.xlsx])\n")
 		case "mockery":
 			MyMock()
 		default:
@@ -276,6 +347,7 @@ func handleCommand() {
 		log.Printf("- go run cmd\\main.go server    (to start server process)\n")
 		log.Printf("- go run cmd\\main.go consumer (to start scheduler consumer process)\n")
 		log.Printf("- go run cmd\\main.go publisher (to start scheduler publisher process)\n")
+		log.Printf("- go run cmd\\main.go import    (to import residents, -file data.xlsx [-report errors.xlsx])\n")
 		log.Printf("- go run cmd\\main.go help      (to see list of command)\n")
 	}
 }
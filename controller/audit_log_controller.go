@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/spreadsheet"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type AuditLogController struct {
+	AuditLogUsecase domain.AuditLogUsecase
+	Config          *bootstrap.Config
+	Cryptos         cryptos.Cryptos
+	Validator       *validator.Validator
+}
+
+func (ctr *AuditLogController) Retrieve(c *gin.Context) {
+	var filter domain.AuditLogFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	logs, meta, err := ctr.AuditLogUsecase.Retrieve(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     logs,
+		Resource: domain.AuditLogTable,
+		Meta:     meta,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+// Export mengirim seluruh audit log yang sesuai filter sebagai berkas unduhan
+// sesuai parameter "format" (csv atau xlsx, bawaan csv).
+func (ctr *AuditLogController) Export(c *gin.Context) {
+	var filter domain.AuditLogFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	format := c.DefaultQuery("format", spreadsheet.FormatCSV)
+	if !spreadsheet.IsSupported(format) {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: spreadsheet.ErrUnsupportedFormat.Error(), Success: false})
+		return
+	}
+
+	filename := fmt.Sprintf("audit-log-%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Content-Type", spreadsheet.ContentType(format))
+	c.Status(http.StatusOK)
+	if err := ctr.AuditLogUsecase.Export(c, filter, format, c.Writer); err != nil {
+		c.Error(err)
+	}
+}
+
+// auditLogRow adalah satu baris tabel audit log dengan waktu yang sudah
+// diformat.
+type auditLogRow struct {
+	domain.AuditLog
+	Time string
+}
+
+// Page menampilkan riwayat perubahan data kependudukan untuk super_admin.
+func (ctr *AuditLogController) Page(c *gin.Context) {
+	var filter domain.AuditLogFilter
+	data := gin.H{
+		"entities": domain.AuditableTables,
+		"actions":  []string{domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionDelete},
+	}
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		data["error"] = err.Error()
+		filter = domain.AuditLogFilter{}
+	} else if err := ctr.Validator.Validate(filter); err != nil {
+		data["error"] = err.Error()
+		filter = domain.AuditLogFilter{}
+	}
+	filter.WithCursor = true
+
+	logs, meta, err := ctr.AuditLogUsecase.Retrieve(c, filter)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	rows := make([]auditLogRow, 0, len(logs))
+	for _, log := range logs {
+		rows = append(rows, auditLogRow{AuditLog: log, Time: time.Unix(log.CreatedAt, 0).Format("2006-01-02 15:04:05")})
+	}
+	data["logs"] = rows
+	data["meta"] = meta
+	data["filter"] = filter
+	data["from"] = formatDate(filter.From)
+	data["to"] = formatDate(filter.To)
+	data["exportURL"] = cursorURL(c, "/admin/audit-logs/export", "")
+	if meta.PrevCursor != "" {
+		data["prevURL"] = cursorURL(c, "/admin/audit-logs", meta.PrevCursor)
+	}
+	if meta.NextCursor != "" {
+		data["nextURL"] = cursorURL(c, "/admin/audit-logs", meta.NextCursor)
+	}
+	c.HTML(http.StatusOK, "dashboard_audit_logs.tmpl", data)
+}
+
+// cursorURL menyusun tautan halaman cursor atau ekspor dengan filter yang
+// sama seperti request saat ini, cursor kosong berarti halaman pertama.
+func cursorURL(c *gin.Context, path string, cursor string) template.URL {
+	query := c.Request.URL.Query()
+	query.Del("page")
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	return template.URL(path + "?" + query.Encode())
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/tokenutil"
+	"github.com/koropati/population-recap/internal/validator"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AuthController melayani autentikasi API. Berbeda dengan LoginController,
+// token hanya dikirim pada body respons dan tidak disimpan pada session.
+type AuthController struct {
+	UserUsecase         domain.UserUsecase
+	AccessTokenUsecase  domain.AccessTokenUsecase
+	RefreshTokenUsecase domain.RefreshTokenUsecase
+	Config              *bootstrap.Config
+	Cryptos             cryptos.Cryptos
+	Validator           *validator.Validator
+}
+
+func (ctr *AuthController) Login(c *gin.Context) {
+	var request domain.LoginUser
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	user, err := ctr.UserUsecase.GetByEmail(c, request.Email)
+	if err != nil || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)) != nil {
+		c.JSON(http.StatusUnauthorized, domain.JsonResponse{Message: "Wrong email or password", Success: false})
+		return
+	}
+
+	if !user.IsActive {
+		message := "User is not active"
+		if user.EmailVerifiedAt == 0 {
+			message = "Email is not verified, please check your inbox or request a new verification link"
+		}
+		c.JSON(http.StatusForbidden, domain.JsonResponse{Message: message, Success: false})
+		return
+	}
+
+	accessToken, refreshToken, err := tokenutil.CreateTokenPair(&user, ctr.Config.AccessTokenSecret, ctr.Config.AccessTokenExpiryHour, ctr.Config.RefreshTokenSecret, ctr.Config.RefreshTokenExpiryHour, ctr.AccessTokenUsecase, ctr.RefreshTokenUsecase)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Message: "Login Successful",
+		Success: true,
+		Data: domain.UserTokenResponse{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		},
+	})
+}
+
+// Refresh menukar refresh token dengan pasangan token baru. Header
+// Authorization bersifat opsional, bila dikirim access token lama ikut dicabut.
+func (ctr *AuthController) Refresh(c *gin.Context) {
+	var request domain.RefreshTokenRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	accessToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	accessToken, refreshToken, err := tokenutil.RotateRefreshToken(request.RefreshToken, accessToken, ctr.Config.AccessTokenSecret, ctr.Config.AccessTokenExpiryHour, ctr.Config.RefreshTokenSecret, ctr.Config.RefreshTokenExpiryHour, ctr.UserUsecase, ctr.AccessTokenUsecase, ctr.RefreshTokenUsecase)
+	if err != nil {
+		message := "Refresh token is invalid or expired"
+		if errors.Is(err, tokenutil.ErrRefreshTokenReused) {
+			message = err.Error()
+		}
+		c.JSON(http.StatusUnauthorized, domain.JsonResponse{Message: message, Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Message: "Token Refreshed",
+		Success: true,
+		Data: domain.UserTokenResponse{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		},
+	})
+}
+
+// Logout mencabut access token pada header Authorization beserta refresh
+// token dari session yang sama. Perangkat lain milik pengguna tetap login.
+func (ctr *AuthController) Logout(c *gin.Context) {
+	accessToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if accessToken == "" {
+		c.JSON(http.StatusUnauthorized, domain.JsonResponse{Message: "Not authorized", Success: false})
+		return
+	}
+
+	err := tokenutil.RevokeToken(accessToken, ctr.Config.AccessTokenSecret, ctr.AccessTokenUsecase, ctr.RefreshTokenUsecase)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{Message: "Logout Successful", Success: true})
+}
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/casbin/casbin"
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type CasbinRuleController struct {
+	CasbinRuleUsecase domain.CasbinRuleUsecase
+	CasbinEnforcer    *casbin.SyncedEnforcer
+	Config            *bootstrap.Config
+	Cryptos           cryptos.Cryptos
+	Validator         *validator.Validator
+}
+
+func (ctr *CasbinRuleController) Retrieve(c *gin.Context) {
+	var filter domain.Filter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	rules, meta, err := ctr.CasbinRuleUsecase.Retrieve(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     rules,
+		Resource: domain.CasbinRuleTable,
+		Meta:     meta,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *CasbinRuleController) AddPolicy(c *gin.Context) {
+	var request domain.CasbinPolicy
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	rule, err := ctr.addPolicy(c, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     rule,
+		Resource: domain.CasbinRuleTable,
+		Message:  "Policy Created",
+		Success:  true,
+	})
+}
+
+func (ctr *CasbinRuleController) AddRoleInheritance(c *gin.Context) {
+	var request domain.CasbinRoleInheritance
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	rule, err := ctr.addRoleInheritance(c, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     rule,
+		Resource: domain.CasbinRuleTable,
+		Message:  "Role Inheritance Created",
+		Success:  true,
+	})
+}
+
+func (ctr *CasbinRuleController) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	if err := ctr.delete(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Message: "Policy Deleted",
+		Success: true,
+	})
+}
+
+// Page menampilkan editor policy untuk super_admin.
+func (ctr *CasbinRuleController) Page(c *gin.Context) {
+	var filter domain.Filter
+	data := gin.H{"msg": c.Query("msg"), "error": c.Query("error")}
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		data["error"] = err.Error()
+		filter = domain.Filter{}
+	}
+	filter.WithPagination = true
+
+	rules, meta, err := ctr.CasbinRuleUsecase.Retrieve(c, filter)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	data["rules"] = rules
+	data["meta"] = meta
+	data["filter"] = filter
+	data["roles"] = domain.UserRoles
+	data["methods"] = []string{"*", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	if meta.Page > 1 {
+		data["prevPage"] = meta.Page - 1
+	}
+	if meta.Page < meta.TotalPages {
+		data["nextPage"] = meta.Page + 1
+	}
+	c.HTML(http.StatusOK, "dashboard_policies.tmpl", data)
+}
+
+func (ctr *CasbinRuleController) PageAddPolicy(c *gin.Context) {
+	var request domain.CasbinPolicy
+	if err := c.ShouldBind(&request); err != nil {
+		redirectPolicyPage(c, "", err.Error())
+		return
+	}
+
+	rule, err := ctr.addPolicy(c, request)
+	if err != nil {
+		redirectPolicyPage(c, "", err.Error())
+		return
+	}
+	redirectPolicyPage(c, "Aturan "+rule.V0+" "+rule.V2+" "+rule.V1+" ditambahkan", "")
+}
+
+func (ctr *CasbinRuleController) PageAddRoleInheritance(c *gin.Context) {
+	var request domain.CasbinRoleInheritance
+	if err := c.ShouldBind(&request); err != nil {
+		redirectPolicyPage(c, "", err.Error())
+		return
+	}
+
+	rule, err := ctr.addRoleInheritance(c, request)
+	if err != nil {
+		redirectPolicyPage(c, "", err.Error())
+		return
+	}
+	redirectPolicyPage(c, "Peran "+rule.V0+" kini mewarisi "+rule.V1, "")
+}
+
+func (ctr *CasbinRuleController) PageDelete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		redirectPolicyPage(c, "", err.Error())
+		return
+	}
+
+	if err := ctr.delete(c, uint(id)); err != nil {
+		redirectPolicyPage(c, "", err.Error())
+		return
+	}
+	redirectPolicyPage(c, "Aturan dihapus", "")
+}
+
+func (ctr *CasbinRuleController) addPolicy(c *gin.Context, request domain.CasbinPolicy) (domain.CasbinRule, error) {
+	if err := ctr.Validator.Validate(request); err != nil {
+		return domain.CasbinRule{}, err
+	}
+	rule, err := ctr.CasbinRuleUsecase.AddPolicy(c, request)
+	if err != nil {
+		return domain.CasbinRule{}, err
+	}
+	return rule, ctr.CasbinEnforcer.LoadPolicy()
+}
+
+func (ctr *CasbinRuleController) addRoleInheritance(c *gin.Context, request domain.CasbinRoleInheritance) (domain.CasbinRule, error) {
+	if err := ctr.Validator.Validate(request); err != nil {
+		return domain.CasbinRule{}, err
+	}
+	rule, err := ctr.CasbinRuleUsecase.AddRoleInheritance(c, request)
+	if err != nil {
+		return domain.CasbinRule{}, err
+	}
+	return rule, ctr.CasbinEnforcer.LoadPolicy()
+}
+
+// delete menghapus aturan lalu langsung memuat ulang policy pada proses ini,
+// proses server lain memuat ulang melalui watcher.
+func (ctr *CasbinRuleController) delete(c *gin.Context, id uint) error {
+	if err := ctr.CasbinRuleUsecase.Delete(c, id); err != nil {
+		return err
+	}
+	return ctr.CasbinEnforcer.LoadPolicy()
+}
+
+func redirectPolicyPage(c *gin.Context, msg string, errMsg string) {
+	query := url.Values{}
+	if msg != "" {
+		query.Set("msg", msg)
+	}
+	if errMsg != "" {
+		query.Set("error", errMsg)
+	}
+	c.Redirect(http.StatusFound, "/admin/policies?"+query.Encode())
+}
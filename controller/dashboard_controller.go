@@ -1,20 +1,201 @@
 package controller
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
 	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/report"
 	"github.com/koropati/population-recap/internal/validator"
 )
 
 type DashboardController struct {
-	Config    *bootstrap.Config
-	Cryptos   cryptos.Cryptos
-	Validator *validator.Validator
+	RecapUsecase  domain.RecapUsecase
+	RegionUsecase domain.RegionUsecase
+	Config        *bootstrap.Config
+	Cryptos       cryptos.Cryptos
+	Validator     *validator.Validator
+}
+
+// pyramidRow adalah satu baris piramida penduduk dengan lebar batang dalam persen.
+type pyramidRow struct {
+	Label       string
+	Male        int64
+	Female      int64
+	MaleWidth   int64
+	FemaleWidth int64
+}
+
+// recapTable adalah satu tabel rincian rekap pada dashboard.
+type recapTable struct {
+	Title string
+	Rows  []domain.RecapCount
 }
 
 func (ctr *DashboardController) Index(c *gin.Context) {
-	c.HTML(http.StatusOK, "dashboard.tmpl", nil)
+	var filter domain.RecapFilter
+	data := gin.H{}
+
+	err := c.ShouldBindQuery(&filter)
+	if err == nil {
+		err = ctr.Validator.Validate(filter)
+	}
+	if err != nil {
+		data["error"] = err.Error()
+		filter = domain.RecapFilter{}
+	}
+
+	recap, err := ctr.RecapUsecase.Population(c, filter)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	regions, _, err := ctr.RegionUsecase.Retrieve(c, domain.Filter{})
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	data["recap"] = recap
+	data["pyramid"] = buildPyramid(recap.AgeGroups)
+	data["breakdowns"] = []recapTable{
+		{Title: "Agama", Rows: recap.Religions},
+		{Title: "Pendidikan", Rows: recap.Educations},
+		{Title: "Status Perkawinan", Rows: recap.MaritalStatuses},
+		{Title: "Pekerjaan", Rows: recap.Occupations},
+	}
+	data["regions"] = regions
+	data["asOf"] = recap.AsOf.Format("2006-01-02")
+	c.HTML(http.StatusOK, "dashboard.tmpl", data)
+}
+
+func (ctr *DashboardController) Mutation(c *gin.Context) {
+	var filter domain.MutationRecapFilter
+	data := gin.H{}
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		data["error"] = err.Error()
+		filter = domain.MutationRecapFilter{}
+	}
+
+	recap, err := ctr.RecapUsecase.MonthlyMutation(c, filter)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	regions, _, err := ctr.RegionUsecase.Retrieve(c, domain.Filter{})
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	data["recap"] = recap
+	data["regions"] = regions
+	data["month"] = recap.PeriodStart.Format("2006-01")
+	c.HTML(http.StatusOK, "dashboard_mutation.tmpl", data)
+}
+
+func (ctr *DashboardController) ExportAgeGroups(c *gin.Context) {
+	ctr.exportPopulation(c, domain.RecapReportAgeGroups)
+}
+
+func (ctr *DashboardController) ExportRegions(c *gin.Context) {
+	ctr.exportPopulation(c, domain.RecapReportRegions)
+}
+
+func (ctr *DashboardController) ExportMutation(c *gin.Context) {
+	var filter domain.MutationRecapFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	recap, err := ctr.RecapUsecase.MutationReport(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+	ctr.writeReport(c, domain.RecapReportMutations, recap)
+}
+
+func (ctr *DashboardController) exportPopulation(c *gin.Context, kind string) {
+	var filter domain.RecapFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err == nil {
+		err = ctr.Validator.Validate(filter)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	recap, err := ctr.RecapUsecase.PopulationReport(c, filter, kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+	ctr.writeReport(c, kind, recap)
+}
+
+// writeReport mengirim laporan sebagai berkas unduhan sesuai parameter
+// "format" (xlsx, csv atau pdf, bawaan xlsx).
+func (ctr *DashboardController) writeReport(c *gin.Context, kind string, recap domain.RecapReport) {
+	format := c.DefaultQuery("format", "xlsx")
+	if !report.IsSupported(format) {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: report.ErrUnsupportedFormat.Error(), Success: false})
+		return
+	}
+
+	now := time.Now().In(domain.Location)
+	signature := domain.ReportSignature{
+		City:     ctr.Config.ReportCity,
+		Date:     now,
+		Position: "Camat " + recap.Kecamatan,
+		Name:     ctr.Config.ReportSignerName,
+		NIP:      ctr.Config.ReportSignerNIP,
+	}
+
+	// Laporan disusun lebih dulu agar kegagalan tidak terkirim sebagai berkas
+	// terpotong dengan status sukses.
+	var body bytes.Buffer
+	if err := report.Write(format, &body, recap, signature); err != nil {
+		c.JSON(http.StatusInternalServerError, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	filename := fmt.Sprintf("rekap-%s-%s.%s", strings.ReplaceAll(kind, "_", "-"), now.Format("20060102"), format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, report.ContentType(format), body.Bytes())
+}
+
+func buildPyramid(ageGroups []domain.RecapCount) []pyramidRow {
+	var largest int64
+	for _, group := range ageGroups {
+		if group.Male > largest {
+			largest = group.Male
+		}
+		if group.Female > largest {
+			largest = group.Female
+		}
+	}
+
+	// Kelompok umur tertua ditampilkan paling atas seperti piramida pada umumnya.
+	rows := make([]pyramidRow, 0, len(ageGroups))
+	for i := len(ageGroups) - 1; i >= 0; i-- {
+		row := pyramidRow{Label: ageGroups[i].Label, Male: ageGroups[i].Male, Female: ageGroups[i].Female}
+		if largest > 0 {
+			row.MaleWidth = row.Male * 100 / largest
+			row.FemaleWidth = row.Female * 100 / largest
+		}
+		rows = append(rows, row)
+	}
+	return rows
 }
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type FamilyController struct {
+	FamilyUsecase domain.FamilyUsecase
+	Config        *bootstrap.Config
+	Cryptos       cryptos.Cryptos
+	Validator     *validator.Validator
+}
+
+func (ctr *FamilyController) Retrieve(c *gin.Context) {
+	var filter domain.Filter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	families, meta, err := ctr.FamilyUsecase.Retrieve(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     families,
+		Resource: domain.FamilyTable,
+		Meta:     meta,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *FamilyController) GetById(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	family, err := ctr.FamilyUsecase.GetById(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.JsonResponse{Message: "Family not found", Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     family,
+		Resource: domain.FamilyTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *FamilyController) Create(c *gin.Context) {
+	var request domain.Family
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	family, err := ctr.FamilyUsecase.Create(c, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     family,
+		Resource: domain.FamilyTable,
+		Message:  "Family Created",
+		Success:  true,
+	})
+}
+
+func (ctr *FamilyController) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	var request domain.Family
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	// Kepala keluarga dan anggota tidak diubah melalui update KK.
+	err = ctr.Validator.ValidateExcept(request, "HeadID", "Members")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	family, err := ctr.FamilyUsecase.Update(c, id, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     family,
+		Resource: domain.FamilyTable,
+		Message:  "Family Updated",
+		Success:  true,
+	})
+}
+
+func (ctr *FamilyController) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.FamilyUsecase.Delete(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Message: "Family Deleted",
+		Success: true,
+	})
+}
+
+// Restore mengembalikan family yang sudah dihapus.
+func (ctr *FamilyController) Restore(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	data, err := ctr.FamilyUsecase.Restore(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     data,
+		Resource: domain.FamilyTable,
+		Message:  "Family Restored",
+		Success:  true,
+	})
+}
+
+func (ctr *FamilyController) AddMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	var request domain.FamilyMember
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	family, err := ctr.FamilyUsecase.AddMember(c, id, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     family,
+		Resource: domain.FamilyTable,
+		Message:  "Family Member Added",
+		Success:  true,
+	})
+}
+
+func (ctr *FamilyController) RemoveMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	residentID, err := uuid.Parse(c.Param("resident_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	family, err := ctr.FamilyUsecase.RemoveMember(c, id, residentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     family,
+		Resource: domain.FamilyTable,
+		Message:  "Family Member Removed",
+		Success:  true,
+	})
+}
@@ -53,17 +53,15 @@ func (ctr *LoginController) Login(c *gin.Context) {
 	}
 
 	if !user.IsActive {
-		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: "User is not active", Success: false})
+		message := "User is not active"
+		if user.EmailVerifiedAt == 0 {
+			message = "Email is not verified, please check your inbox or request a new verification link"
+		}
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: message, Success: false})
 		return
 	}
 
-	accessToken, err := tokenutil.CreateAccessToken(&user, ctr.Config.AccessTokenSecret, ctr.Config.AccessTokenExpiryHour, ctr.AccessTokenUsecase)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
-		return
-	}
-
-	refreshToken, err := tokenutil.CreateRefreshToken(&user, ctr.Config.RefreshTokenSecret, ctr.Config.RefreshTokenExpiryHour, accessToken, ctr.RefreshTokenUsecase)
+	accessToken, refreshToken, err := tokenutil.CreateTokenPair(&user, ctr.Config.AccessTokenSecret, ctr.Config.AccessTokenExpiryHour, ctr.Config.RefreshTokenSecret, ctr.Config.RefreshTokenExpiryHour, ctr.AccessTokenUsecase, ctr.RefreshTokenUsecase)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
 		return
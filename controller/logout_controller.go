@@ -6,9 +6,11 @@ import (
 
 	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/koropati/population-recap/bootstrap"
 	"github.com/koropati/population-recap/domain"
 	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/tokenutil"
 	"github.com/koropati/population-recap/internal/validator"
 	"github.com/koropati/population-recap/middleware"
 )
@@ -30,11 +32,19 @@ func (ctr *LogoutController) Logout(c *gin.Context) {
 	}
 	accessToken, errGetAccess := middleware.GetAuthContext(c, ctr.Cryptos, "access")
 	if errGetAccess != nil {
-		log.Printf("Error Get Access Token : %v\n", errGetRefresh)
+		log.Printf("Error Get Access Token : %v\n", errGetAccess)
 	}
 
-	ctr.AccessTokenUsecase.Delete(c, accessToken)
-	ctr.RefreshTokenUsecase.Delete(c, refreshToken)
+	// Hanya token pada session perangkat ini yang dicabut, session lain
+	// milik pengguna yang sama tetap berlaku.
+	if sessionID := ctr.sessionID(c, accessToken, refreshToken); sessionID != uuid.Nil {
+		if err := tokenutil.RevokeSession(sessionID, ctr.AccessTokenUsecase, ctr.RefreshTokenUsecase); err != nil {
+			log.Printf("Error Revoke Session : %v\n", err)
+		}
+	} else {
+		ctr.AccessTokenUsecase.Delete(c, accessToken)
+		ctr.RefreshTokenUsecase.Delete(c, refreshToken)
+	}
 
 	session := sessions.Default(c) // Get the current session
 
@@ -52,3 +62,15 @@ func (ctr *LogoutController) Logout(c *gin.Context) {
 	// Redirect to the login page or other appropriate location
 	c.Redirect(http.StatusFound, "/login")
 }
+
+// sessionID mencari session dari refresh token atau access token pada cookie.
+// Token yang dibuat sebelum session tersedia menghasilkan uuid.Nil.
+func (ctr *LogoutController) sessionID(c *gin.Context, accessToken string, refreshToken string) uuid.UUID {
+	if stored, err := ctr.RefreshTokenUsecase.GetByToken(c, refreshToken); err == nil && stored.SessionID != uuid.Nil {
+		return stored.SessionID
+	}
+	if stored, err := ctr.AccessTokenUsecase.GetByToken(c, accessToken); err == nil {
+		return stored.SessionID
+	}
+	return uuid.Nil
+}
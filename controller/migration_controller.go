@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+// certificateRow adalah satu baris daftar penduduk pada surat pindah/datang.
+type certificateRow struct {
+	No int
+	domain.Resident
+}
+
+type MigrationController struct {
+	MigrationUsecase domain.MigrationUsecase
+	Config           *bootstrap.Config
+	Cryptos          cryptos.Cryptos
+	Validator        *validator.Validator
+}
+
+func (ctr *MigrationController) Retrieve(c *gin.Context) {
+	var filter domain.Filter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	migrations, meta, err := ctr.MigrationUsecase.Retrieve(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     migrations,
+		Resource: domain.MigrationTable,
+		Meta:     meta,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *MigrationController) GetById(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	migration, err := ctr.MigrationUsecase.GetById(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.JsonResponse{Message: "Migration not found", Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     migration,
+		Resource: domain.MigrationTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *MigrationController) Create(c *gin.Context) {
+	var request domain.MigrationRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	// Penduduk yang datang selalu terdaftar di wilayah tujuan sehingga
+	// region_id tidak perlu diisi per penduduk.
+	if request.DestinationRegionID != nil {
+		for i := range request.Residents {
+			request.Residents[i].RegionID = *request.DestinationRegionID
+		}
+	}
+
+	// NIK penduduk yang datang diterbitkan daerah asal sehingga tidak harus
+	// berawalan kode wilayah Bangli, formatnya diperiksa saat pendaftaran.
+	except := make([]string, 0, len(request.Residents))
+	for i := range request.Residents {
+		except = append(except, fmt.Sprintf("Residents[%d].NIK", i))
+	}
+	err = ctr.Validator.ValidateExcept(request, except...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	migration, err := ctr.MigrationUsecase.Create(c, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     migration,
+		Resource: domain.MigrationTable,
+		Message:  "Migration Registered",
+		Success:  true,
+	})
+}
+
+// Certificate menampilkan surat keterangan pindah/datang yang siap dicetak.
+func (ctr *MigrationController) Certificate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	migration, err := ctr.MigrationUsecase.GetById(c, id)
+	if err != nil {
+		c.String(http.StatusNotFound, "Migration not found")
+		return
+	}
+
+	residents := []certificateRow{}
+	seen := map[uuid.UUID]bool{}
+	for _, mutation := range migration.Mutations {
+		if mutation.Resident == nil || seen[mutation.ResidentID] {
+			continue
+		}
+		seen[mutation.ResidentID] = true
+		residents = append(residents, certificateRow{No: len(residents) + 1, Resident: *mutation.Resident})
+	}
+
+	c.HTML(http.StatusOK, "migration_certificate.tmpl", gin.H{
+		"appName":   ctr.Config.AppName,
+		"migration": migration,
+		"residents": residents,
+		"moveDate":  migration.MoveDate.Format("02-01-2006"),
+	})
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type MutationController struct {
+	MutationUsecase domain.MutationUsecase
+	Config          *bootstrap.Config
+	Cryptos         cryptos.Cryptos
+	Validator       *validator.Validator
+}
+
+func (ctr *MutationController) Retrieve(c *gin.Context) {
+	var filter domain.Filter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	mutations, meta, err := ctr.MutationUsecase.Retrieve(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     mutations,
+		Resource: domain.MutationTable,
+		Meta:     meta,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *MutationController) GetById(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	mutation, err := ctr.MutationUsecase.GetById(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.JsonResponse{Message: "Mutation not found", Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     mutation,
+		Resource: domain.MutationTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *MutationController) RegisterBirth(c *gin.Context) {
+	var request domain.BirthRegistration
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	mutation, err := ctr.MutationUsecase.RegisterBirth(c, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     mutation,
+		Resource: domain.MutationTable,
+		Message:  "Birth Registered",
+		Success:  true,
+	})
+}
+
+func (ctr *MutationController) RegisterDeath(c *gin.Context) {
+	var request domain.DeathRegistration
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	mutation, err := ctr.MutationUsecase.RegisterDeath(c, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     mutation,
+		Resource: domain.MutationTable,
+		Message:  "Death Registered",
+		Success:  true,
+	})
+}
+
+func (ctr *MutationController) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.MutationUsecase.Delete(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Message: "Mutation Deleted",
+		Success: true,
+	})
+}
+
+// Restore mengembalikan mutation yang sudah dihapus.
+func (ctr *MutationController) Restore(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	data, err := ctr.MutationUsecase.Restore(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     data,
+		Resource: domain.MutationTable,
+		Message:  "Mutation Restored",
+		Success:  true,
+	})
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type RecapController struct {
+	RecapUsecase domain.RecapUsecase
+	Config       *bootstrap.Config
+	Cryptos      cryptos.Cryptos
+	Validator    *validator.Validator
+}
+
+func (ctr *RecapController) Population(c *gin.Context) {
+	var filter domain.RecapFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	recap, err := ctr.RecapUsecase.Population(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     recap,
+		Resource: domain.RecapResource,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *RecapController) MonthlyMutation(c *gin.Context) {
+	var filter domain.MutationRecapFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	recap, err := ctr.RecapUsecase.MonthlyMutation(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     recap,
+		Resource: domain.RecapResource,
+		Message:  "Success",
+		Success:  true,
+	})
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type RegionController struct {
+	RegionUsecase domain.RegionUsecase
+	Config        *bootstrap.Config
+	Cryptos       cryptos.Cryptos
+	Validator     *validator.Validator
+}
+
+func (ctr *RegionController) Retrieve(c *gin.Context) {
+	var filter domain.Filter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	regions, meta, err := ctr.RegionUsecase.Retrieve(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     regions,
+		Resource: domain.RegionTable,
+		Meta:     meta,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *RegionController) GetById(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	region, err := ctr.RegionUsecase.GetById(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.JsonResponse{Message: "Region not found", Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     region,
+		Resource: domain.RegionTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *RegionController) GetChildren(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	regions, err := ctr.RegionUsecase.GetChildren(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     regions,
+		Resource: domain.RegionTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *RegionController) Create(c *gin.Context) {
+	var request domain.Region
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	request.ID, err = uuid.NewUUID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.RegionUsecase.Create(c, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	region, err := ctr.RegionUsecase.GetById(c, request.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     region,
+		Resource: domain.RegionTable,
+		Message:  "Region Created",
+		Success:  true,
+	})
+}
+
+func (ctr *RegionController) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	var request domain.Region
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	region, err := ctr.RegionUsecase.Update(c, id, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     region,
+		Resource: domain.RegionTable,
+		Message:  "Region Updated",
+		Success:  true,
+	})
+}
+
+func (ctr *RegionController) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.RegionUsecase.Delete(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Message: "Region Deleted",
+		Success: true,
+	})
+}
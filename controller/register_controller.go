@@ -1,20 +1,24 @@
 package controller
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/koropati/population-recap/bootstrap"
 	"github.com/koropati/population-recap/domain"
 	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/mailer"
 	"github.com/koropati/population-recap/internal/validator"
 )
 
 type RegisterController struct {
-	UserUsecase domain.UserUsecase
-	Config      *bootstrap.Config
-	Cryptos     cryptos.Cryptos
-	Validator   *validator.Validator
+	UserUsecase                   domain.UserUsecase
+	VerificationEmailTokenUsecase domain.VerificationEmailTokenUsecase
+	Config                        *bootstrap.Config
+	Cryptos                       cryptos.Cryptos
+	Validator                     *validator.Validator
+	Mailer                        mailer.Mailer
 }
 
 func (ctr *RegisterController) Index(c *gin.Context) {
@@ -48,8 +52,15 @@ func (ctr *RegisterController) Register(c *gin.Context) {
 		return
 	}
 
+	// Akun tetap tersimpan walaupun email gagal dibuat, pengguna dapat
+	// meminta tautan baru dari halaman verifikasi.
+	err = sendVerificationEmail(c, ctr.Config, ctr.Cryptos, ctr.Mailer, ctr.VerificationEmailTokenUsecase, userData)
+	if err != nil {
+		log.Printf("Error Create Verification Email : %v\n", err)
+	}
+
 	c.JSON(http.StatusOK, domain.JsonResponse{
-		Message: "Registration Successful",
+		Message: "Registration Successful, please check your email to verify your account",
 		Success: true,
 	})
 }
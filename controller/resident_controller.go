@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type ResidentController struct {
+	ResidentUsecase       domain.ResidentUsecase
+	ResidentSearchUsecase domain.ResidentSearchUsecase
+	RegionUsecase         domain.RegionUsecase
+	Config                *bootstrap.Config
+	Cryptos               cryptos.Cryptos
+	Validator             *validator.Validator
+}
+
+func (ctr *ResidentController) Retrieve(c *gin.Context) {
+	var filter domain.Filter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	residents, meta, err := ctr.ResidentUsecase.Retrieve(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     residents,
+		Resource: domain.ResidentTable,
+		Meta:     meta,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+// Search mencari penduduk untuk pelayanan di loket dengan sebagian nama,
+// awalan NIK/KK atau alamat, diurutkan dari yang paling cocok.
+func (ctr *ResidentController) Search(c *gin.Context) {
+	var query domain.ResidentSearchQuery
+
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	results, err := ctr.ResidentSearchUsecase.Search(c, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     results,
+		Resource: domain.ResidentTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *ResidentController) GetById(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	resident, err := ctr.ResidentUsecase.GetById(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.JsonResponse{Message: "Resident not found", Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     resident,
+		Resource: domain.ResidentTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *ResidentController) GetPeriods(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	periods, err := ctr.ResidentUsecase.GetPeriods(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     periods,
+		Resource: domain.ResidencePeriodTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *ResidentController) Create(c *gin.Context) {
+	var request domain.Resident
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	request.ID, err = uuid.NewUUID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.ResidentUsecase.Create(c, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	resident, err := ctr.ResidentUsecase.GetById(c, request.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     resident,
+		Resource: domain.ResidentTable,
+		Message:  "Resident Created",
+		Success:  true,
+	})
+}
+
+func (ctr *ResidentController) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	var request domain.Resident
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	resident, err := ctr.ResidentUsecase.Update(c, id, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     resident,
+		Resource: domain.ResidentTable,
+		Message:  "Resident Updated",
+		Success:  true,
+	})
+}
+
+func (ctr *ResidentController) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.ResidentUsecase.Delete(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Message: "Resident Deleted",
+		Success: true,
+	})
+}
+
+// Restore mengembalikan resident yang sudah dihapus.
+func (ctr *ResidentController) Restore(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	data, err := ctr.ResidentUsecase.Restore(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     data,
+		Resource: domain.ResidentTable,
+		Message:  "Resident Restored",
+		Success:  true,
+	})
+}
+
+// residentPageRow adalah satu baris halaman penduduk dengan tanggal lahir
+// yang sudah diformat.
+type residentPageRow struct {
+	domain.Resident
+	Born string
+}
+
+// Page menampilkan daftar penduduk dengan pagination cursor sehingga seluruh
+// penduduk satu desa dapat ditelusuri tanpa melambat di halaman yang jauh.
+// Parameter region menyaring wilayah beserta seluruh turunannya.
+func (ctr *ResidentController) Page(c *gin.Context) {
+	var filter domain.Filter
+	data := gin.H{
+		"sorts":  []string{"name", "nik", "birth_date", "created_at"},
+		"region": c.Query("region"),
+	}
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		data["error"] = err.Error()
+		filter = domain.Filter{}
+	}
+	filter.WithCursor = true
+	if region := c.Query("region"); region != "" {
+		filter.Filters = append(filter.Filters, "region:"+region)
+	}
+
+	residents, meta, err := ctr.ResidentUsecase.Retrieve(c, filter)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	regions, _, err := ctr.RegionUsecase.Retrieve(c, domain.Filter{})
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	rows := make([]residentPageRow, 0, len(residents))
+	for _, resident := range residents {
+		rows = append(rows, residentPageRow{Resident: resident, Born: resident.BirthPlace + ", " + formatDate(resident.BirthDate)})
+	}
+	data["residents"] = rows
+	data["regions"] = regions
+	data["meta"] = meta
+	data["filter"] = filter
+	if meta.PrevCursor != "" {
+		data["prevURL"] = cursorURL(c, "/residents", meta.PrevCursor)
+	}
+	if meta.NextCursor != "" {
+		data["nextURL"] = cursorURL(c, "/residents", meta.NextCursor)
+	}
+	c.HTML(http.StatusOK, "dashboard_residents.tmpl", data)
+}
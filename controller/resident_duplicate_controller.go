@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type ResidentDuplicateController struct {
+	ResidentDuplicateUsecase domain.ResidentDuplicateUsecase
+	Config                   *bootstrap.Config
+	Cryptos                  cryptos.Cryptos
+	Validator                *validator.Validator
+}
+
+func (ctr *ResidentDuplicateController) Retrieve(c *gin.Context) {
+	var filter domain.Filter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	duplicates, meta, err := ctr.ResidentDuplicateUsecase.Retrieve(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     duplicates,
+		Resource: domain.ResidentDuplicateTable,
+		Meta:     meta,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *ResidentDuplicateController) GetById(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	duplicate, err := ctr.ResidentDuplicateUsecase.GetById(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.JsonResponse{Message: "Resident duplicate not found", Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     duplicate,
+		Resource: domain.ResidentDuplicateTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+// Merge menggabungkan pasangan penduduk ke penduduk keep_id.
+func (ctr *ResidentDuplicateController) Merge(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	var request domain.ResidentDuplicateMerge
+	err = c.ShouldBind(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	duplicate, err := ctr.ResidentDuplicateUsecase.Merge(c, id, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     duplicate,
+		Resource: domain.ResidentDuplicateTable,
+		Message:  "Residents Merged",
+		Success:  true,
+	})
+}
+
+// Dismiss menandai pasangan bukan duplikat.
+func (ctr *ResidentDuplicateController) Dismiss(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	duplicate, err := ctr.ResidentDuplicateUsecase.Dismiss(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     duplicate,
+		Resource: domain.ResidentDuplicateTable,
+		Message:  "Resident Duplicate Dismissed",
+		Success:  true,
+	})
+}
+
+// residentDuplicatePageRow adalah satu pasangan pada antrean tinjauan dengan
+// tanggal lahir dan waktu tinjauan yang sudah diformat serta penanda
+// penduduk yang dipertahankan bila sudah digabung.
+type residentDuplicatePageRow struct {
+	domain.ResidentDuplicate
+	ResidentBorn  string
+	DuplicateBorn string
+	Reviewed      string
+	KeptResident  bool
+	KeptDuplicate bool
+}
+
+// Page menampilkan antrean tinjauan data ganda untuk super_admin, bawaan
+// pasangan yang masih menunggu dengan nilai kecocokan tertinggi di atas.
+func (ctr *ResidentDuplicateController) Page(c *gin.Context) {
+	var filter domain.Filter
+	status := c.DefaultQuery("status", domain.ResidentDuplicateStatusPending)
+	data := gin.H{
+		"statuses": []string{domain.ResidentDuplicateStatusPending, domain.ResidentDuplicateStatusMerged, domain.ResidentDuplicateStatusDismissed},
+		"status":   status,
+		"msg":      c.Query("msg"),
+		"error":    c.Query("error"),
+	}
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		data["error"] = err.Error()
+		filter = domain.Filter{}
+	}
+	filter.WithCursor = true
+	filter.Filters = append(filter.Filters, "status:"+status)
+
+	duplicates, meta, err := ctr.ResidentDuplicateUsecase.Retrieve(c, filter)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	rows := make([]residentDuplicatePageRow, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		row := residentDuplicatePageRow{ResidentDuplicate: duplicate}
+		if duplicate.Resident != nil {
+			row.ResidentBorn = formatDate(duplicate.Resident.BirthDate)
+		}
+		if duplicate.Duplicate != nil {
+			row.DuplicateBorn = formatDate(duplicate.Duplicate.BirthDate)
+		}
+		if duplicate.ReviewedAt > 0 {
+			row.Reviewed = time.Unix(duplicate.ReviewedAt, 0).Format("2006-01-02 15:04:05")
+		}
+		if duplicate.KeptID != nil {
+			row.KeptResident = *duplicate.KeptID == duplicate.ResidentID
+			row.KeptDuplicate = *duplicate.KeptID == duplicate.DuplicateID
+		}
+		rows = append(rows, row)
+	}
+	data["duplicates"] = rows
+	data["meta"] = meta
+	data["filter"] = filter
+	if meta.PrevCursor != "" {
+		data["prevURL"] = cursorURL(c, "/admin/resident-duplicates", meta.PrevCursor)
+	}
+	if meta.NextCursor != "" {
+		data["nextURL"] = cursorURL(c, "/admin/resident-duplicates", meta.NextCursor)
+	}
+	c.HTML(http.StatusOK, "dashboard_resident_duplicates.tmpl", data)
+}
+
+func (ctr *ResidentDuplicateController) PageMerge(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		redirectResidentDuplicatePage(c, "", err.Error())
+		return
+	}
+
+	var request domain.ResidentDuplicateMerge
+	err = c.ShouldBind(&request)
+	if err != nil {
+		redirectResidentDuplicatePage(c, "", err.Error())
+		return
+	}
+
+	duplicate, err := ctr.ResidentDuplicateUsecase.Merge(c, id, request)
+	if err != nil {
+		redirectResidentDuplicatePage(c, "", err.Error())
+		return
+	}
+	kept := duplicate.Resident
+	if *duplicate.KeptID == duplicate.DuplicateID {
+		kept = duplicate.Duplicate
+	}
+	if kept == nil {
+		redirectResidentDuplicatePage(c, "Data ganda digabung", "")
+		return
+	}
+	redirectResidentDuplicatePage(c, "Data ganda digabung ke "+kept.Name+" ("+kept.NIK+")", "")
+}
+
+func (ctr *ResidentDuplicateController) PageDismiss(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		redirectResidentDuplicatePage(c, "", err.Error())
+		return
+	}
+
+	if _, err := ctr.ResidentDuplicateUsecase.Dismiss(c, id); err != nil {
+		redirectResidentDuplicatePage(c, "", err.Error())
+		return
+	}
+	redirectResidentDuplicatePage(c, "Pasangan ditandai bukan data ganda", "")
+}
+
+func redirectResidentDuplicatePage(c *gin.Context, msg string, errMsg string) {
+	query := url.Values{}
+	if msg != "" {
+		query.Set("msg", msg)
+	}
+	if errMsg != "" {
+		query.Set("error", errMsg)
+	}
+	c.Redirect(http.StatusFound, "/admin/resident-duplicates?"+query.Encode())
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/spreadsheet"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type ResidentImportController struct {
+	ResidentImportUsecase domain.ResidentImportUsecase
+	Config                *bootstrap.Config
+	Cryptos               cryptos.Cryptos
+	Validator             *validator.Validator
+}
+
+// Import menerima berkas CSV atau XLSX pada field "file". Bila parameter
+// "report" diisi csv atau xlsx, laporan kesalahan per baris dikirim sebagai
+// berkas unduhan, selain itu hasil impor dikirim sebagai JSON.
+func (ctr *ResidentImportController) Import(c *gin.Context) {
+	reportFormat := c.PostForm("report")
+	if reportFormat != "" && !spreadsheet.IsSupported(reportFormat) {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: spreadsheet.ErrUnsupportedFormat.Error(), Success: false})
+		return
+	}
+
+	result, err := ctr.importFile(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	if reportFormat != "" {
+		ctr.writeReport(c, reportFormat, result)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     result,
+		Resource: domain.ResidentTable,
+		Message:  fmt.Sprintf("%d rows imported, %d rows failed", result.Created+result.Updated, result.Failed),
+		Success:  true,
+	})
+}
+
+// Page menampilkan formulir impor penduduk.
+func (ctr *ResidentImportController) Page(c *gin.Context) {
+	c.HTML(http.StatusOK, "dashboard_import.tmpl", gin.H{"columns": domain.ResidentImportColumns})
+}
+
+// Upload memproses formulir impor dan menampilkan ringkasan beserta
+// kesalahan per baris, atau mengunduh laporan kesalahan bila diminta.
+func (ctr *ResidentImportController) Upload(c *gin.Context) {
+	data := gin.H{"columns": domain.ResidentImportColumns}
+
+	result, err := ctr.importFile(c)
+	if err != nil {
+		data["error"] = err.Error()
+		c.HTML(http.StatusBadRequest, "dashboard_import.tmpl", data)
+		return
+	}
+
+	reportFormat := c.PostForm("report")
+	if spreadsheet.IsSupported(reportFormat) && len(result.Errors) > 0 {
+		ctr.writeReport(c, reportFormat, result)
+		return
+	}
+
+	data["result"] = result
+	c.HTML(http.StatusOK, "dashboard_import.tmpl", data)
+}
+
+func (ctr *ResidentImportController) importFile(c *gin.Context) (domain.ImportResult, error) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+	format, err := spreadsheet.FormatFromFilename(header.Filename)
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+	defer file.Close()
+
+	return ctr.ResidentImportUsecase.Import(c, format, file)
+}
+
+func (ctr *ResidentImportController) writeReport(c *gin.Context, format string, result domain.ImportResult) {
+	filename := fmt.Sprintf("import-report-%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Content-Type", spreadsheet.ContentType(format))
+	c.Header("X-Import-Total", fmt.Sprint(result.TotalRows))
+	c.Header("X-Import-Created", fmt.Sprint(result.Created))
+	c.Header("X-Import-Updated", fmt.Sprint(result.Updated))
+	c.Header("X-Import-Failed", fmt.Sprint(result.Failed))
+	c.Status(http.StatusOK)
+	if err := ctr.ResidentImportUsecase.WriteReport(format, result, c.Writer); err != nil {
+		c.Error(err)
+	}
+}
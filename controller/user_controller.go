@@ -0,0 +1,524 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/tokenutil"
+	"github.com/koropati/population-recap/internal/validator"
+	"github.com/koropati/population-recap/middleware"
+)
+
+type UserController struct {
+	UserUsecase         domain.UserUsecase
+	RegionUsecase       domain.RegionUsecase
+	AccessTokenUsecase  domain.AccessTokenUsecase
+	RefreshTokenUsecase domain.RefreshTokenUsecase
+	Config              *bootstrap.Config
+	Cryptos             cryptos.Cryptos
+	Validator           *validator.Validator
+}
+
+func (ctr *UserController) Retrieve(c *gin.Context) {
+	var filter domain.Filter
+
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	users, meta, err := ctr.UserUsecase.Retrieve(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     users,
+		Resource: domain.UserTable,
+		Meta:     meta,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *UserController) GetById(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	user, err := ctr.UserUsecase.GetById(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.JsonResponse{Message: "User not found", Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     user,
+		Resource: domain.UserTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+// Me mengembalikan data pengguna pemilik token.
+func (ctr *UserController) Me(c *gin.Context) {
+	userID, _ := middleware.GetUserContext(c, ctr.Cryptos)
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, domain.JsonResponse{Message: "not authorized", Success: false})
+		return
+	}
+
+	user, err := ctr.UserUsecase.GetById(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.JsonResponse{Message: "User not found", Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     user,
+		Resource: domain.UserTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+func (ctr *UserController) Activate(c *gin.Context) {
+	ctr.updateStatus(c, true)
+}
+
+// Deactivate menonaktifkan pengguna dan mencabut seluruh tokennya sehingga
+// pengguna langsung keluar dari semua perangkat.
+func (ctr *UserController) Deactivate(c *gin.Context) {
+	ctr.updateStatus(c, false)
+}
+
+func (ctr *UserController) UpdateRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	var request domain.UpdateUserRole
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	user, err := ctr.setRole(c, id, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     user,
+		Resource: domain.UserTable,
+		Message:  "User role updated",
+		Success:  true,
+	})
+}
+
+// Delete menghapus pengguna (soft delete) dan mencabut seluruh tokennya.
+// Pengguna dapat dikembalikan melalui Restore sampai masa retensi habis.
+func (ctr *UserController) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	if err := ctr.deleteUser(c, id); err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Message: "User deleted",
+		Success: true,
+	})
+}
+
+// Restore mengembalikan pengguna yang sudah dihapus.
+func (ctr *UserController) Restore(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	user, err := ctr.UserUsecase.Restore(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     user,
+		Resource: domain.UserTable,
+		Message:  "User restored",
+		Success:  true,
+	})
+}
+
+// GetRegions mengembalikan wilayah tugas pengguna.
+func (ctr *UserController) GetRegions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	regions, err := ctr.UserUsecase.GetRegions(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     regions,
+		Resource: domain.UserRegionTable,
+		Message:  "Success",
+		Success:  true,
+	})
+}
+
+// UpdateRegions mengganti wilayah tugas pengguna. Cakupan wilayah dimuat
+// ulang pada setiap request sehingga token pengguna tidak perlu dicabut.
+func (ctr *UserController) UpdateRegions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	var request domain.UpdateUserRegions
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	if _, err := ctr.UserUsecase.GetById(c, id); err != nil {
+		c.JSON(http.StatusNotFound, domain.JsonResponse{Message: "User not found", Success: false})
+		return
+	}
+	if err := ctr.UserUsecase.SetRegions(c, id, request.RegionIDs); err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	regions, err := ctr.UserUsecase.GetRegions(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     regions,
+		Resource: domain.UserRegionTable,
+		Message:  "User regions updated",
+		Success:  true,
+	})
+}
+
+// userRegionOption adalah pilihan wilayah tugas pada formulir wilayah tugas.
+type userRegionOption struct {
+	domain.Region
+	Selected bool
+}
+
+// userPageRow adalah satu baris halaman pengguna beserta wilayah tugasnya.
+type userPageRow struct {
+	domain.User
+	Regions []domain.Region
+}
+
+// Page menampilkan daftar pengguna untuk super_admin beserta pencarian
+// berdasarkan nama, email dan peran. Wilayah tugas seluruh baris dibaca
+// sekaligus, sedangkan daftar seluruh wilayah hanya dimuat untuk satu
+// formulir wilayah tugas pengguna edit_regions.
+func (ctr *UserController) Page(c *gin.Context) {
+	var filter domain.Filter
+	data := gin.H{"roles": domain.UserRoles, "msg": c.Query("msg"), "error": c.Query("error")}
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		data["error"] = err.Error()
+		filter = domain.Filter{}
+	}
+	filter.WithPagination = true
+
+	users, meta, err := ctr.UserUsecase.Retrieve(c, filter)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	ids := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	assignments, err := ctr.UserUsecase.GetAssignments(c, ids)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	assigned := make(map[uuid.UUID][]domain.Region, len(users))
+	for _, assignment := range assignments {
+		if assignment.Region != nil {
+			assigned[assignment.UserID] = append(assigned[assignment.UserID], *assignment.Region)
+		}
+	}
+
+	rows := make([]userPageRow, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, userPageRow{User: user, Regions: assigned[user.ID]})
+	}
+
+	if editID, err := uuid.Parse(c.Query("edit_regions")); err == nil {
+		for _, row := range rows {
+			if row.ID != editID {
+				continue
+			}
+			regions, _, err := ctr.RegionUsecase.Retrieve(c, domain.Filter{})
+			if err != nil {
+				data["error"] = err.Error()
+			}
+			selected := make(map[uuid.UUID]bool, len(row.Regions))
+			for _, region := range row.Regions {
+				selected[region.ID] = true
+			}
+			options := make([]userRegionOption, 0, len(regions))
+			for _, region := range regions {
+				options = append(options, userRegionOption{Region: region, Selected: selected[region.ID]})
+			}
+			data["editUser"] = row.User
+			data["regionOptions"] = options
+		}
+	}
+
+	data["users"] = rows
+	data["meta"] = meta
+	data["filter"] = filter
+	if meta.Page > 1 {
+		data["prevPage"] = meta.Page - 1
+	}
+	if meta.Page < meta.TotalPages {
+		data["nextPage"] = meta.Page + 1
+	}
+	c.HTML(http.StatusOK, "dashboard_users.tmpl", data)
+}
+
+func (ctr *UserController) PageActivate(c *gin.Context) {
+	ctr.pageUpdateStatus(c, true)
+}
+
+func (ctr *UserController) PageDeactivate(c *gin.Context) {
+	ctr.pageUpdateStatus(c, false)
+}
+
+func (ctr *UserController) PageDelete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+
+	user, err := ctr.UserUsecase.GetById(c, id)
+	if err != nil {
+		redirectUserPage(c, "", "User not found")
+		return
+	}
+	if err := ctr.deleteUser(c, id); err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+	redirectUserPage(c, user.Name+" dihapus", "")
+}
+
+func (ctr *UserController) PageRestore(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+
+	user, err := ctr.UserUsecase.Restore(c, id)
+	if err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+	redirectUserPage(c, user.Name+" dikembalikan", "")
+}
+
+func (ctr *UserController) PageUpdateRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+
+	var request domain.UpdateUserRole
+	err = c.ShouldBind(&request)
+	if err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+
+	user, err := ctr.setRole(c, id, request)
+	if err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+	redirectUserPage(c, "Peran "+user.Name+" diubah menjadi "+user.Role, "")
+}
+
+func (ctr *UserController) PageUpdateRegions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+
+	regionIDs := []uuid.UUID{}
+	for _, value := range c.PostFormArray("region_ids") {
+		regionID, err := uuid.Parse(value)
+		if err != nil {
+			redirectUserPage(c, "", err.Error())
+			return
+		}
+		regionIDs = append(regionIDs, regionID)
+	}
+
+	user, err := ctr.UserUsecase.GetById(c, id)
+	if err != nil {
+		redirectUserPage(c, "", "User not found")
+		return
+	}
+	if err := ctr.UserUsecase.SetRegions(c, id, regionIDs); err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+	redirectUserPage(c, "Wilayah tugas "+user.Name+" disimpan", "")
+}
+
+func (ctr *UserController) updateStatus(c *gin.Context, isActive bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	user, err := ctr.setStatus(c, id, isActive)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	message := "User activated"
+	if !isActive {
+		message = "User deactivated"
+	}
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Data:     user,
+		Resource: domain.UserTable,
+		Message:  message,
+		Success:  true,
+	})
+}
+
+func (ctr *UserController) pageUpdateStatus(c *gin.Context, isActive bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+
+	user, err := ctr.setStatus(c, id, isActive)
+	if err != nil {
+		redirectUserPage(c, "", err.Error())
+		return
+	}
+
+	if isActive {
+		redirectUserPage(c, user.Name+" diaktifkan", "")
+		return
+	}
+	redirectUserPage(c, user.Name+" dinonaktifkan", "")
+}
+
+func (ctr *UserController) setStatus(c *gin.Context, id uuid.UUID, isActive bool) (domain.User, error) {
+	if err := ctr.checkNotSelf(c, id); err != nil {
+		return domain.User{}, err
+	}
+
+	if err := ctr.UserUsecase.UpdateStatus(c, id, isActive); err != nil {
+		return domain.User{}, err
+	}
+	if !isActive {
+		if err := tokenutil.RevokeByUserID(id, ctr.AccessTokenUsecase, ctr.RefreshTokenUsecase); err != nil {
+			return domain.User{}, err
+		}
+	}
+	return ctr.UserUsecase.GetById(c, id)
+}
+
+func (ctr *UserController) deleteUser(c *gin.Context, id uuid.UUID) error {
+	if err := ctr.checkNotSelf(c, id); err != nil {
+		return err
+	}
+
+	if err := ctr.UserUsecase.Delete(c, id); err != nil {
+		return err
+	}
+	return tokenutil.RevokeByUserID(id, ctr.AccessTokenUsecase, ctr.RefreshTokenUsecase)
+}
+
+// setRole mengubah peran pengguna. Peran tersimpan di dalam token sehingga
+// seluruh token lama dicabut dan pengguna perlu login ulang.
+func (ctr *UserController) setRole(c *gin.Context, id uuid.UUID, request domain.UpdateUserRole) (domain.User, error) {
+	if err := ctr.Validator.Validate(request); err != nil {
+		return domain.User{}, err
+	}
+	if err := ctr.checkNotSelf(c, id); err != nil {
+		return domain.User{}, err
+	}
+
+	if err := ctr.UserUsecase.UpdateRole(c, id, request.Role); err != nil {
+		return domain.User{}, err
+	}
+	if err := tokenutil.RevokeByUserID(id, ctr.AccessTokenUsecase, ctr.RefreshTokenUsecase); err != nil {
+		return domain.User{}, err
+	}
+	return ctr.UserUsecase.GetById(c, id)
+}
+
+// checkNotSelf mencegah super_admin mengunci akunnya sendiri.
+func (ctr *UserController) checkNotSelf(c *gin.Context, id uuid.UUID) error {
+	userID, _ := middleware.GetUserContext(c, ctr.Cryptos)
+	if userID == id.String() {
+		return errors.New("you can not change the status or role of your own account or delete it")
+	}
+	return nil
+}
+
+func redirectUserPage(c *gin.Context, msg string, errMsg string) {
+	query := url.Values{}
+	if msg != "" {
+		query.Set("msg", msg)
+	}
+	if errMsg != "" {
+		query.Set("error", errMsg)
+	}
+	c.Redirect(http.StatusFound, "/admin/users?"+query.Encode())
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/bootstrap"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"github.com/koropati/population-recap/internal/mailer"
+	"github.com/koropati/population-recap/internal/tokenutil"
+	"github.com/koropati/population-recap/internal/urlutil"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+type VerificationEmailController struct {
+	Config                        *bootstrap.Config
+	Cryptos                       cryptos.Cryptos
+	Validator                     *validator.Validator
+	UserUsecase                   domain.UserUsecase
+	VerificationEmailTokenUsecase domain.VerificationEmailTokenUsecase
+	Mailer                        mailer.Mailer
+}
+
+// VerifyEmail mengaktifkan akun dari tautan pada email verifikasi dan
+// menampilkan hasilnya. Halaman yang sama menyediakan form kirim ulang bila
+// tautan sudah kedaluwarsa.
+func (ctr *VerificationEmailController) VerifyEmail(c *gin.Context) {
+	data := map[string]interface{}{"success": false}
+
+	token, err := ctr.Cryptos.Decrypt(c.Query("token"))
+	if err != nil || !ctr.VerificationEmailTokenUsecase.IsValid(c, token) {
+		data["msg"] = "The verification link is invalid or has expired, please request a new one."
+		c.HTML(http.StatusBadRequest, "verify_email.tmpl", data)
+		return
+	}
+
+	userId, err := ctr.VerificationEmailTokenUsecase.GetUserID(c, token)
+	if err != nil {
+		data["msg"] = err.Error()
+		c.HTML(http.StatusBadRequest, "verify_email.tmpl", data)
+		return
+	}
+
+	err = ctr.VerificationEmailTokenUsecase.Revoke(c, token)
+	if err != nil {
+		data["msg"] = err.Error()
+		c.HTML(http.StatusBadRequest, "verify_email.tmpl", data)
+		return
+	}
+
+	err = ctr.UserUsecase.VerifyEmail(c, userId)
+	if err != nil {
+		data["msg"] = err.Error()
+		c.HTML(http.StatusBadRequest, "verify_email.tmpl", data)
+		return
+	}
+
+	data["success"] = true
+	data["msg"] = "Your email has been verified, you can now sign in."
+	c.HTML(http.StatusOK, "verify_email.tmpl", data)
+}
+
+// resendVerificationEmailMessage adalah jawaban kirim ulang verifikasi untuk
+// email apa pun agar tidak dapat dipakai menebak email yang terdaftar.
+const resendVerificationEmailMessage = "If the email is registered and not yet verified, a new verification link has been sent"
+
+// ResendVerificationEmail mengirim tautan verifikasi baru dan mencabut
+// tautan yang pernah dikirim sebelumnya. Email yang tidak terdaftar atau
+// sudah terverifikasi mendapat jawaban yang sama.
+func (ctr *VerificationEmailController) ResendVerificationEmail(c *gin.Context) {
+	var request domain.ResendVerificationEmail
+
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	err = ctr.Validator.Validate(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.JsonResponse{Message: err.Error(), Success: false})
+		return
+	}
+
+	user, err := ctr.UserUsecase.GetByEmail(c, request.Email)
+	if err == nil && user.EmailVerifiedAt == 0 {
+		ctr.VerificationEmailTokenUsecase.RevokeByUserID(c, user.ID)
+
+		err = sendVerificationEmail(c, ctr.Config, ctr.Cryptos, ctr.Mailer, ctr.VerificationEmailTokenUsecase, user)
+		if err != nil {
+			log.Printf("Error Resend Verification Email : %v\n", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, domain.JsonResponse{
+		Message: resendVerificationEmailMessage,
+		Success: true,
+	})
+}
+
+// sendVerificationEmail membuat token verifikasi dan mengirim tautannya ke
+// email pengguna di background.
+func sendVerificationEmail(c *gin.Context, config *bootstrap.Config, cryptos cryptos.Cryptos, mail mailer.Mailer, verificationEmailTokenUsecase domain.VerificationEmailTokenUsecase, user domain.User) error {
+	verificationToken, err := tokenutil.CreateVerificationEmailToken(&user, config.VerificationEmailExpiryHour, verificationEmailTokenUsecase)
+	if err != nil {
+		return err
+	}
+
+	encVerificationToken, err := cryptos.Encrypt(verificationToken)
+	if err != nil {
+		return err
+	}
+
+	urlVerification := urlutil.CreateUrlVerificationEmail(c.Request, encVerificationToken)
+	go func() {
+		errSendEmail := mail.SendVerificationEmail(mailer.VerificationEmailData{
+			AppName:           config.AppName,
+			Name:              user.Name,
+			Email:             user.Email,
+			From:              config.SmtpSenderMail,
+			VerificationToken: verificationToken,
+			UrlRedirect:       urlVerification,
+			UrlVerification:   urlVerification,
+		})
+		if errSendEmail != nil {
+			log.Printf("Error Send Email : %v\n", errSendEmail)
+		}
+	}()
+	return nil
+}
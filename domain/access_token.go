@@ -10,10 +10,13 @@ const (
 	AccessTokenTable = "access_tokens"
 )
 
+// AccessToken memiliki SessionID yang sama dengan refresh token pasangannya
+// sehingga keluar dari satu perangkat hanya mencabut token perangkat itu.
 type AccessToken struct {
 	ID        uuid.UUID `gorm:"primaryKey" json:"id"`
 	Token     string    `gorm:"type:longtext" json:"token"`
 	UserID    uuid.UUID `gorm:"type:char(36);not null;index;foreignKey:ID" json:"user_id"`
+	SessionID uuid.UUID `gorm:"type:char(36);index" json:"session_id"`
 	Revoked   bool      `gorm:"default:false" json:"revoked"`
 	CreatedAt int64     `gorm:"autoCreateTime" json:"created_at"`
 	ExpiresAt int64     `gorm:"index" json:"expires_at"`
@@ -21,7 +24,10 @@ type AccessToken struct {
 
 type AccessTokenRepository interface {
 	Create(c context.Context, accessToken AccessToken) error
+	GetByToken(c context.Context, token string) (accessToken AccessToken, err error)
 	Revoke(c context.Context, token string) error
+	RevokeByID(c context.Context, id uuid.UUID) error
+	RevokeBySessionID(c context.Context, sessionID uuid.UUID) error
 	RevokeByUserID(c context.Context, userID uuid.UUID) error
 	IsValid(c context.Context, token string) bool
 	Delete(c context.Context, token string) error
@@ -30,7 +36,10 @@ type AccessTokenRepository interface {
 
 type AccessTokenUsecase interface {
 	Create(c context.Context, accessToken AccessToken) error
+	GetByToken(c context.Context, token string) (accessToken AccessToken, err error)
 	Revoke(c context.Context, token string) error
+	RevokeByID(c context.Context, id uuid.UUID) error
+	RevokeBySessionID(c context.Context, sessionID uuid.UUID) error
 	RevokeByUserID(c context.Context, userID uuid.UUID) error
 	IsValid(c context.Context, token string) bool
 	Delete(c context.Context, token string) error
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditLogTable = "audit_logs"
+
+	// AuditActorContext adalah kunci context tempat middleware menyimpan
+	// pengguna yang melakukan perubahan data.
+	AuditActorContext = "x-audit-actor"
+
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+
+	// AuditActorSystem adalah peran pencatat untuk perubahan di luar request
+	// HTTP, misalnya impor dari command line dan scheduler.
+	AuditActorSystem = "system"
+)
+
+// AuditableTables adalah tabel data kependudukan yang setiap perubahannya
+// dicatat pada audit log.
+var AuditableTables = []string{
+	RegionTable,
+	ResidentTable,
+	ResidencePeriodTable,
+	FamilyTable,
+	FamilyMemberTable,
+	MutationTable,
+	MigrationTable,
+}
+
+// AuditLogColumns adalah header berkas ekspor audit log.
+var AuditLogColumns = []string{"waktu", "pengguna", "peran", "ip", "entitas", "id_entitas", "aksi", "sebelum", "sesudah"}
+
+// AuditLog adalah satu perubahan data kependudukan. Before dan After hanya
+// memuat kolom yang berubah pada aksi update, seluruh kolom data baru pada
+// aksi create dan seluruh kolom data lama pada aksi delete.
+type AuditLog struct {
+	ID        uuid.UUID       `gorm:"primaryKey;type:char(36);index:idx_audit_logs_entity_sort,priority:2;index:idx_audit_logs_action_sort,priority:2;index:idx_audit_logs_created_at_sort,priority:2" json:"id"`
+	ActorID   string          `gorm:"size:36;index" json:"actor_id"`
+	ActorRole string          `gorm:"size:32" json:"actor_role"`
+	IPAddress string          `gorm:"size:45" json:"ip_address"`
+	Entity    string          `gorm:"size:64;index:idx_audit_logs_entity;index:idx_audit_logs_entity_sort,priority:1" json:"entity"`
+	EntityID  string          `gorm:"size:64;index:idx_audit_logs_entity" json:"entity_id"`
+	Action    string          `gorm:"size:16;index:idx_audit_logs_action_sort,priority:1" json:"action"`
+	Before    json.RawMessage `gorm:"type:json" json:"before"`
+	After     json.RawMessage `gorm:"type:json" json:"after"`
+	CreatedAt int64           `gorm:"autoCreateTime;index:idx_audit_logs_created_at_sort,priority:1" json:"created_at"`
+}
+
+// AuditActor adalah pengguna dan alamat IP asal request yang mengubah data.
+type AuditActor struct {
+	UserID    string
+	Role      string
+	IPAddress string
+}
+
+// AuditLogFilter menyaring audit log. Search mencari ID entitas, From dan To
+// membatasi tanggal perubahan termasuk kedua ujungnya.
+type AuditLogFilter struct {
+	Filter
+	Entity  string    `json:"entity" form:"entity"`
+	Action  string    `json:"action" form:"action" validate:"omitempty,oneof=create update delete"`
+	ActorID string    `json:"actor_id" form:"actor_id"`
+	From    time.Time `json:"from" form:"from" time_format:"2006-01-02"`
+	To      time.Time `json:"to" form:"to" time_format:"2006-01-02"`
+}
+
+// WithAuditActor menyimpan pengguna pencatat pada context di luar request
+// HTTP.
+func WithAuditActor(c context.Context, actor AuditActor) context.Context {
+	return context.WithValue(c, AuditActorContext, actor)
+}
+
+// AuditActorFromContext mengembalikan pengguna pencatat pada context, context
+// tanpa pengguna dicatat sebagai system.
+func AuditActorFromContext(c context.Context) AuditActor {
+	actor, ok := c.Value(AuditActorContext).(AuditActor)
+	if !ok {
+		return AuditActor{Role: AuditActorSystem}
+	}
+	return actor
+}
+
+type AuditLogRepository interface {
+	Retrieve(c context.Context, filter AuditLogFilter) (logs []AuditLog, meta MetaResponse, err error)
+}
+
+type AuditLogUsecase interface {
+	Retrieve(c context.Context, filter AuditLogFilter) (logs []AuditLog, meta MetaResponse, err error)
+	// Export menulis seluruh audit log yang sesuai filter sebagai CSV atau
+	// XLSX.
+	Export(c context.Context, filter AuditLogFilter, format string, w io.Writer) error
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	CasbinRuleTable = "casbin_rules"
+	CasbinSeedTable = "casbin_seeds"
+
+	// CasbinPolicyType adalah aturan akses peran terhadap path dan method,
+	// CasbinGroupingType adalah pewarisan peran (peran V0 mewarisi seluruh
+	// aturan akses peran V1).
+	CasbinPolicyType   = "p"
+	CasbinGroupingType = "g"
+)
+
+// CasbinRule adalah satu baris policy Casbin. Kolom V0 sampai V5 mengikuti
+// urutan nilai pada berkas policy.csv, misalnya p, admin, /residents/*, GET.
+type CasbinRule struct {
+	ID    uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	PType string `gorm:"size:8;index" json:"ptype"`
+	V0    string `gorm:"size:255;index" json:"v0"`
+	V1    string `gorm:"size:255;index" json:"v1"`
+	V2    string `gorm:"size:255" json:"v2"`
+	V3    string `gorm:"size:255" json:"v3"`
+	V4    string `gorm:"size:255" json:"v4"`
+	V5    string `gorm:"size:255" json:"v5"`
+}
+
+// CasbinSeed mencatat baris policy.csv yang pernah disalin ke casbin_rules
+// sehingga aturan baru pada policy.csv ikut ditambahkan saat start tanpa
+// memunculkan lagi aturan yang sudah dihapus melalui editor policy.
+type CasbinSeed struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Line      string `gorm:"size:512;uniqueIndex" json:"line"`
+	CreatedAt int64  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CasbinPolicy adalah permintaan aturan akses. Path mengikuti keyMatch
+// Casbin tanpa prefix /api/v1 sehingga satu aturan berlaku untuk halaman
+// HTML dan API, Method * berarti seluruh method.
+type CasbinPolicy struct {
+	Role   string `json:"role" form:"role" validate:"required,max=64,excludesall=0x2C "`
+	Path   string `json:"path" form:"path" validate:"required,startswith=/,max=255,excludesall=0x2C "`
+	Method string `json:"method" form:"method" validate:"required,oneof=GET POST PUT PATCH DELETE *"`
+}
+
+// CasbinRoleInheritance adalah permintaan pewarisan peran, Role mendapat
+// seluruh aturan akses ParentRole.
+type CasbinRoleInheritance struct {
+	Role       string `json:"role" form:"role" validate:"required,max=64,excludesall=0x2C "`
+	ParentRole string `json:"parent_role" form:"parent_role" validate:"required,max=64,excludesall=0x2C ,nefield=Role"`
+}
+
+type CasbinRuleRepository interface {
+	Create(c context.Context, rule *CasbinRule) error
+	Retrieve(c context.Context, filter Filter) (rules []CasbinRule, meta MetaResponse, err error)
+	GetById(c context.Context, id uint) (rule CasbinRule, err error)
+	// Exists memeriksa apakah aturan dengan nilai yang sama sudah tersimpan.
+	Exists(c context.Context, rule CasbinRule) (bool, error)
+	Delete(c context.Context, id uint) error
+}
+
+type CasbinRuleUsecase interface {
+	Retrieve(c context.Context, filter Filter) (rules []CasbinRule, meta MetaResponse, err error)
+	GetById(c context.Context, id uint) (rule CasbinRule, err error)
+	AddPolicy(c context.Context, policy CasbinPolicy) (CasbinRule, error)
+	AddRoleInheritance(c context.Context, inheritance CasbinRoleInheritance) (CasbinRule, error)
+	Delete(c context.Context, id uint) error
+}
@@ -0,0 +1,10 @@
+package domain
+
+const DataMigrationTable = "data_migrations"
+
+// DataMigration mencatat perbaikan data satu kali yang sudah dijalankan saat
+// aplikasi dimulai agar tidak dijalankan lagi pada setiap start.
+type DataMigration struct {
+	Name      string `gorm:"primaryKey;size:64" json:"name"`
+	AppliedAt int64  `gorm:"autoCreateTime" json:"applied_at"`
+}
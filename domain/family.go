@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	FamilyTable       = "families"
+	FamilyMemberTable = "family_members"
+
+	RelationshipKepalaKeluarga = "kepala_keluarga"
+	RelationshipSuami          = "suami"
+	RelationshipIstri          = "istri"
+	RelationshipAnak           = "anak"
+	RelationshipMenantu        = "menantu"
+	RelationshipCucu           = "cucu"
+	RelationshipOrangTua       = "orang_tua"
+	RelationshipMertua         = "mertua"
+	RelationshipFamiliLain     = "famili_lain"
+	RelationshipPembantu       = "pembantu"
+	RelationshipLainnya        = "lainnya"
+)
+
+// ErrAlreadyFamilyMember dikembalikan bila penduduk masih tercatat sebagai
+// anggota KK aktif lain.
+var ErrAlreadyFamilyMember = errors.New("resident is already a member of another family")
+
+// Family adalah satu Kartu Keluarga (KK). Kepala keluarga selalu tercatat
+// juga sebagai anggota dengan hubungan kepala_keluarga. HeadID kosong bila
+// kepala keluarganya dihapus permanen tanpa pengganti. Members hanya memuat
+// keanggotaan yang masih berlaku.
+type Family struct {
+	ID        uuid.UUID      `gorm:"primaryKey;type:char(36);index:idx_families_address_sort,priority:2;index:idx_families_created_at_sort,priority:2;index:idx_families_updated_at_sort,priority:2" json:"id"`
+	KKNumber  string         `gorm:"unique;size:16" json:"kk_number" validate:"required,kk"`
+	HeadID    uuid.UUID      `gorm:"type:char(36);index" json:"head_id" validate:"required"`
+	Address   string         `gorm:"size:255;index:idx_families_address_fulltext,class:FULLTEXT;index:idx_families_address_sort,priority:1" json:"address" validate:"required,max=255"`
+	RT        string         `gorm:"size:3" json:"rt" validate:"omitempty,numeric,max=3"`
+	RW        string         `gorm:"size:3" json:"rw" validate:"omitempty,numeric,max=3"`
+	RegionID  uuid.UUID      `gorm:"type:char(36);not null;index" json:"region_id" validate:"required"`
+	Members   []FamilyMember `gorm:"foreignKey:FamilyID" json:"members,omitempty" validate:"dive"`
+	CreatedAt int64          `gorm:"autoCreateTime;index:idx_families_created_at_sort,priority:1" json:"created_at"`
+	UpdatedAt int64          `gorm:"autoUpdateTime;index:idx_families_updated_at_sort,priority:1" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// FamilyMember adalah keanggotaan penduduk dalam KK yang berlaku mulai
+// ValidFrom sampai sebelum ValidUntil. Keanggotaan yang berakhir tidak dihapus
+// agar susunan KK pada tanggal lampau tetap dapat disusun ulang.
+type FamilyMember struct {
+	ID           uuid.UUID  `gorm:"primaryKey;type:char(36)" json:"id"`
+	FamilyID     uuid.UUID  `gorm:"type:char(36);not null;index" json:"family_id"`
+	ResidentID   uuid.UUID  `gorm:"type:char(36);not null;index" json:"resident_id" validate:"required"`
+	Relationship string     `gorm:"size:32;index" json:"relationship" validate:"required,oneof=kepala_keluarga suami istri anak menantu cucu orang_tua mertua famili_lain pembantu lainnya"`
+	ValidFrom    time.Time  `gorm:"type:date;index" json:"valid_from"`
+	ValidUntil   *time.Time `gorm:"type:date;index" json:"valid_until"`
+	Resident     *Resident  `gorm:"foreignKey:ResidentID" json:"resident,omitempty" validate:"-"`
+	CreatedAt    int64      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type FamilyRepository interface {
+	Create(c context.Context, family Family) error
+	Retrieve(c context.Context, filter Filter) (families []Family, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (family Family, err error)
+	GetByKKNumber(c context.Context, kkNumber string) (family Family, err error)
+	Update(c context.Context, id uuid.UUID, data Family) (family Family, err error)
+	Delete(c context.Context, id uuid.UUID) error
+	// Restore mengembalikan KK yang sudah dihapus.
+	Restore(c context.Context, id uuid.UUID) error
+	// Purge menghapus permanen KK yang dihapus sebelum waktu tersebut.
+	Purge(c context.Context, before time.Time) (int64, error)
+	AddMember(c context.Context, member FamilyMember) error
+	// RemoveMember mengakhiri keanggotaan yang masih berlaku per tanggal until.
+	RemoveMember(c context.Context, familyID uuid.UUID, residentID uuid.UUID, until time.Time) error
+	// GetMemberByResidentID mengembalikan keanggotaan KK yang masih berlaku.
+	GetMemberByResidentID(c context.Context, residentID uuid.UUID) (member FamilyMember, err error)
+}
+
+type FamilyUsecase interface {
+	Create(c context.Context, family Family) (Family, error)
+	Retrieve(c context.Context, filter Filter) (families []Family, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (family Family, err error)
+	GetByKKNumber(c context.Context, kkNumber string) (family Family, err error)
+	Update(c context.Context, id uuid.UUID, data Family) (family Family, err error)
+	Delete(c context.Context, id uuid.UUID) error
+	Restore(c context.Context, id uuid.UUID) (Family, error)
+	AddMember(c context.Context, familyID uuid.UUID, member FamilyMember) (Family, error)
+	RemoveMember(c context.Context, familyID uuid.UUID, residentID uuid.UUID) (Family, error)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MigrationTable          = "migrations"
+	CertificateCounterTable = "certificate_counters"
+
+	// MigrationKindInternal adalah pindah antar desa/banjar di dalam Bangli,
+	// MigrationKindDeparture pindah ke luar Bangli dan MigrationKindArrival
+	// datang dari luar Bangli.
+	MigrationKindInternal  = "internal"
+	MigrationKindDeparture = "departure"
+	MigrationKindArrival   = "arrival"
+
+	CertificatePrefixMove    = "SKP"
+	CertificatePrefixArrival = "SKD"
+)
+
+// Migration adalah satu peristiwa pindah/datang yang dapat mencakup satu atau
+// beberapa penduduk. Setiap penduduk menghasilkan peristiwa move_out pada
+// wilayah asal dan/atau move_in pada wilayah tujuan sehingga rekap mutasi
+// bulanan membebankan masing-masing ke desa yang benar. Wilayah di luar Bangli
+// dicatat sebagai alamat pada OriginAddress atau DestinationAddress.
+type Migration struct {
+	ID                  uuid.UUID  `gorm:"primaryKey;type:char(36);index:idx_migrations_move_date_sort,priority:2;index:idx_migrations_created_at_sort,priority:2" json:"id"`
+	CertificateNumber   string     `gorm:"unique;size:32" json:"certificate_number"`
+	Kind                string     `gorm:"size:16;index" json:"kind"`
+	FamilyID            *uuid.UUID `gorm:"type:char(36);index" json:"family_id"`
+	OriginRegionID      *uuid.UUID `gorm:"type:char(36);index" json:"origin_region_id"`
+	DestinationRegionID *uuid.UUID `gorm:"type:char(36);index" json:"destination_region_id"`
+	OriginAddress       string     `gorm:"size:255" json:"origin_address"`
+	DestinationAddress  string     `gorm:"size:255" json:"destination_address"`
+	MoveDate            time.Time  `gorm:"type:date;index:idx_migrations_move_date_sort,priority:1" json:"move_date"`
+	Reason              string     `gorm:"size:255" json:"reason"`
+	OriginRegion        *Region    `gorm:"foreignKey:OriginRegionID" json:"origin_region,omitempty"`
+	DestinationRegion   *Region    `gorm:"foreignKey:DestinationRegionID" json:"destination_region,omitempty"`
+	Mutations           []Mutation `gorm:"foreignKey:MigrationID" json:"mutations,omitempty"`
+	CreatedAt           int64      `gorm:"autoCreateTime;index:idx_migrations_created_at_sort,priority:1" json:"created_at"`
+	UpdatedAt           int64      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CertificateCounter menyimpan nomor urut terakhir surat pindah/datang per
+// awalan dan bulan, misalnya SKP/2024/05/. Barisnya dikunci saat nomor
+// diterbitkan agar migrasi yang bersamaan tidak mendapat nomor yang sama.
+type CertificateCounter struct {
+	Period string `gorm:"primaryKey;size:16" json:"period"`
+	Last   int64  `gorm:"not null" json:"last"`
+}
+
+// MigrationRequest adalah data pelaporan pindah/datang. Untuk internal dan
+// departure isi FamilyID (pindah satu KK) atau ResidentIDs (pindah
+// perorangan). Untuk arrival isi Residents dengan biodata penduduk yang datang.
+type MigrationRequest struct {
+	Kind                string      `json:"kind" validate:"required,oneof=internal departure arrival"`
+	FamilyID            *uuid.UUID  `json:"family_id"`
+	ResidentIDs         []uuid.UUID `json:"resident_ids"`
+	Residents           []Resident  `json:"residents" validate:"omitempty,dive"`
+	DestinationRegionID *uuid.UUID  `json:"destination_region_id"`
+	OriginAddress       string      `json:"origin_address" validate:"max=255"`
+	DestinationAddress  string      `json:"destination_address" validate:"max=255"`
+	MoveDate            time.Time   `json:"move_date" validate:"required"`
+	Reason              string      `json:"reason" validate:"max=255"`
+}
+
+type MigrationRepository interface {
+	// Create menerbitkan nomor surat, menerapkan perpindahan pada data
+	// penduduk dan KK lalu menyimpan migrasi beserta peristiwa mutasinya dalam
+	// satu transaksi. Arrivals adalah penduduk baru untuk MigrationKindArrival.
+	Create(c context.Context, migration *Migration, arrivals []Resident) error
+	Retrieve(c context.Context, filter Filter) (migrations []Migration, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (migration Migration, err error)
+	GetByCertificateNumber(c context.Context, certificateNumber string) (migration Migration, err error)
+}
+
+type MigrationUsecase interface {
+	Create(c context.Context, request MigrationRequest) (Migration, error)
+	Retrieve(c context.Context, filter Filter) (migrations []Migration, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (migration Migration, err error)
+	GetByCertificateNumber(c context.Context, certificateNumber string) (migration Migration, err error)
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	MutationTable = "mutations"
+
+	MutationTypeBirth   = "birth"
+	MutationTypeDeath   = "death"
+	MutationTypeMoveIn  = "move_in"
+	MutationTypeMoveOut = "move_out"
+
+	DeathCauseSakitBiasa    = "sakit_biasa"
+	DeathCauseWabahPenyakit = "wabah_penyakit"
+	DeathCauseKecelakaan    = "kecelakaan"
+	DeathCauseKriminalitas  = "kriminalitas"
+	DeathCauseBunuhDiri     = "bunuh_diri"
+	DeathCauseLainnya       = "lainnya"
+)
+
+// ErrMutationNotDeletable dikembalikan saat menghapus peristiwa kelahiran atau
+// pindah/datang. Peristiwa tersebut mengubah data penduduk, KK dan masa
+// tinggal sehingga menghapus peristiwanya saja membuat rekap mutasi bulanan
+// tidak lagi sesuai dengan jumlah penduduk.
+var ErrMutationNotDeletable = errors.New("only death events can be deleted, correct births and migrations through the resident and migration records")
+
+// Mutation adalah satu peristiwa kependudukan (lahir, mati, datang, pindah).
+// RegionID adalah wilayah yang dibebani peristiwa tersebut pada rekap mutasi
+// bulanan. Place dan Cause hanya diisi untuk peristiwa kematian, MigrationID
+// hanya diisi untuk peristiwa pindah/datang.
+type Mutation struct {
+	ID          uuid.UUID      `gorm:"primaryKey;type:char(36);index:idx_mutations_type_sort,priority:2;index:idx_mutations_event_date_sort,priority:2;index:idx_mutations_created_at_sort,priority:2" json:"id"`
+	Type        string         `gorm:"size:16;index:idx_mutations_type_sort,priority:1" json:"type" validate:"required,oneof=birth death move_in move_out"`
+	ResidentID  uuid.UUID      `gorm:"type:char(36);not null;index" json:"resident_id" validate:"required"`
+	RegionID    uuid.UUID      `gorm:"type:char(36);not null;index" json:"region_id" validate:"required"`
+	EventDate   time.Time      `gorm:"type:date;index:idx_mutations_event_date_sort,priority:1" json:"event_date" validate:"required"`
+	Place       string         `gorm:"size:128" json:"place" validate:"max=128"`
+	Cause       string         `gorm:"size:32;index" json:"cause" validate:"omitempty,oneof=sakit_biasa wabah_penyakit kecelakaan kriminalitas bunuh_diri lainnya"`
+	Notes       string         `gorm:"size:255" json:"notes" validate:"max=255"`
+	MigrationID *uuid.UUID     `gorm:"type:char(36);index" json:"migration_id"`
+	Resident    *Resident      `gorm:"foreignKey:ResidentID" json:"resident,omitempty" validate:"-"`
+	CreatedAt   int64          `gorm:"autoCreateTime;index:idx_mutations_created_at_sort,priority:1" json:"created_at"`
+	UpdatedAt   int64          `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// BirthRegistration adalah data pelaporan kelahiran. Bayi langsung
+// didaftarkan sebagai anggota KK orang tuanya sehingga wilayah penduduk
+// mengikuti wilayah KK tersebut.
+type BirthRegistration struct {
+	FamilyID     uuid.UUID `json:"family_id" validate:"required"`
+	Relationship string    `json:"relationship" validate:"omitempty,oneof=anak cucu famili_lain lainnya"`
+	NIK          string    `json:"nik" validate:"required,nik,nik_sex=Sex,nik_birth_date=BirthDate"`
+	Name         string    `json:"name" validate:"required,max=255"`
+	BirthPlace   string    `json:"birth_place" validate:"required,max=128"`
+	BirthDate    time.Time `json:"birth_date" validate:"required"`
+	Sex          string    `json:"sex" validate:"required,oneof=male female"`
+	Religion     string    `json:"religion" validate:"required,oneof=islam kristen katolik hindu buddha konghucu kepercayaan"`
+	BloodType    string    `json:"blood_type" validate:"omitempty,oneof=A B AB O unknown"`
+	FatherName   string    `json:"father_name" validate:"max=255"`
+	MotherName   string    `json:"mother_name" validate:"max=255"`
+	Notes        string    `json:"notes" validate:"max=255"`
+}
+
+// DeathRegistration adalah data pelaporan kematian. NewHeadID boleh diisi
+// untuk menunjuk kepala keluarga pengganti bila yang meninggal adalah kepala
+// keluarga, bila kosong pengganti dipilih otomatis.
+type DeathRegistration struct {
+	ResidentID uuid.UUID  `json:"resident_id" validate:"required"`
+	DeathDate  time.Time  `json:"death_date" validate:"required"`
+	Place      string     `json:"place" validate:"required,max=128"`
+	Cause      string     `json:"cause" validate:"required,oneof=sakit_biasa wabah_penyakit kecelakaan kriminalitas bunuh_diri lainnya"`
+	NewHeadID  *uuid.UUID `json:"new_head_id"`
+	Notes      string     `json:"notes" validate:"max=255"`
+}
+
+type MutationRepository interface {
+	Create(c context.Context, mutation Mutation) error
+	// RegisterBirth menyimpan penduduk baru, keanggotaan KK dan peristiwa
+	// kelahiran dalam satu transaksi.
+	RegisterBirth(c context.Context, resident Resident, member FamilyMember, mutation Mutation) error
+	// RegisterDeath menandai penduduk meninggal, mengakhiri masa tinggal dan
+	// keanggotaan KK-nya (member boleh nil), menetapkan kepala keluarga
+	// pengganti (successor boleh nil) dan mencatat peristiwa kematian dalam
+	// satu transaksi.
+	RegisterDeath(c context.Context, mutation Mutation, member *FamilyMember, successor *FamilyMember) error
+	Retrieve(c context.Context, filter Filter) (mutations []Mutation, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (mutation Mutation, err error)
+	// RevertDeath menghapus peristiwa kematian sekaligus membatalkan
+	// akibatnya dalam satu transaksi: penduduk kembali aktif, masa tinggal dan
+	// keanggotaan KK yang diakhiri pada tanggal kematian dibuka kembali dan
+	// kepala keluarga pengganti dikembalikan ke hubungan sebelumnya.
+	RevertDeath(c context.Context, mutation Mutation) error
+	// Restore mengembalikan peristiwa yang sudah dihapus kecuali peristiwa
+	// kematian yang akibatnya sudah dibatalkan. Peristiwa kelahiran dan
+	// pindah/datang hanya dapat terhapus sebelum ErrMutationNotDeletable
+	// berlaku, tanpa mengubah data penduduknya, sehingga memulihkannya
+	// menyelaraskan kembali rekap mutasi.
+	Restore(c context.Context, id uuid.UUID) error
+	// Purge menghapus permanen peristiwa yang dihapus sebelum waktu tersebut.
+	Purge(c context.Context, before time.Time) (int64, error)
+}
+
+// MutationUsecase tidak menyediakan Create karena setiap peristiwa dicatat
+// melalui alur pelaporannya: kelahiran, kematian dan pindah/datang.
+type MutationUsecase interface {
+	RegisterBirth(c context.Context, registration BirthRegistration) (Mutation, error)
+	RegisterDeath(c context.Context, registration DeathRegistration) (Mutation, error)
+	Retrieve(c context.Context, filter Filter) (mutations []Mutation, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (mutation Mutation, err error)
+	Delete(c context.Context, id uuid.UUID) error
+	Restore(c context.Context, id uuid.UUID) (Mutation, error)
+}
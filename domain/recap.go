@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	RecapResource = "recaps"
+
+	RecapDimensionRegion        = "region"
+	RecapDimensionAgeGroup      = "age_group"
+	RecapDimensionReligion      = "religion"
+	RecapDimensionEducation     = "education"
+	RecapDimensionOccupation    = "occupation"
+	RecapDimensionMaritalStatus = "marital_status"
+
+	// AgeGroupSize dan AgeGroupCount membentuk piramida penduduk 0-4, 5-9,
+	// ..., 70-74 dan kelompok terakhir 75+.
+	AgeGroupSize  = 5
+	AgeGroupCount = 16
+)
+
+// RecapFilter adalah parameter rekap. RegionCode kosong berarti seluruh
+// wilayah, GroupBy adalah level wilayah untuk rincian per wilayah dan AsOf
+// adalah tanggal acuan perhitungan umur dan keberadaan penduduk.
+type RecapFilter struct {
+	RegionCode string    `json:"region_code" form:"region_code"`
+	GroupBy    string    `json:"group_by" form:"group_by" validate:"omitempty,oneof=kecamatan desa dusun"`
+	AsOf       time.Time `json:"as_of" form:"as_of" time_format:"2006-01-02"`
+}
+
+// RecapQuery adalah bentuk RecapFilter yang sudah diselesaikan oleh usecase
+// dan siap dipakai repository.
+type RecapQuery struct {
+	RegionCode      string
+	GroupCodeLength int
+	AsOf            time.Time
+}
+
+// RecapRow adalah satu baris hasil agregasi per kunci dan jenis kelamin.
+type RecapRow struct {
+	Key   string
+	Sex   string
+	Total int64
+}
+
+type RecapCount struct {
+	Key    string `json:"key"`
+	Label  string `json:"label"`
+	Male   int64  `json:"male"`
+	Female int64  `json:"female"`
+	Total  int64  `json:"total"`
+}
+
+type RegionRecap struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Level      string `json:"level"`
+	Male       int64  `json:"male"`
+	Female     int64  `json:"female"`
+	Total      int64  `json:"total"`
+	Households int64  `json:"households"`
+}
+
+type PopulationRecap struct {
+	RegionCode      string        `json:"region_code"`
+	RegionName      string        `json:"region_name"`
+	GroupBy         string        `json:"group_by"`
+	AsOf            time.Time     `json:"as_of"`
+	Total           int64         `json:"total"`
+	Male            int64         `json:"male"`
+	Female          int64         `json:"female"`
+	Households      int64         `json:"households"`
+	AgeGroups       []RecapCount  `json:"age_groups"`
+	Religions       []RecapCount  `json:"religions"`
+	Educations      []RecapCount  `json:"educations"`
+	Occupations     []RecapCount  `json:"occupations"`
+	MaritalStatuses []RecapCount  `json:"marital_statuses"`
+	Regions         []RegionRecap `json:"regions"`
+}
+
+// MutationRecapFilter adalah parameter rekap mutasi bulanan. Month cukup
+// berisi tahun dan bulan laporan, kosong berarti bulan berjalan.
+type MutationRecapFilter struct {
+	RegionCode string    `json:"region_code" form:"region_code"`
+	Month      time.Time `json:"month" form:"month" time_format:"2006-01"`
+}
+
+// MutationRow adalah satu baris hasil agregasi mutasi per kunci dan jenis
+// peristiwa.
+type MutationRow struct {
+	Key   string
+	Type  string
+	Total int64
+}
+
+// MutationBalance adalah satu baris neraca mutasi: penduduk awal bulan +
+// lahir - mati + datang - pindah = penduduk akhir bulan. Expected adalah hasil
+// hitung neraca sedangkan Closing adalah jumlah penduduk tercatat pada akhir
+// bulan, keduanya harus sama agar Reconciled bernilai true.
+type MutationBalance struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Opening    int64  `json:"opening"`
+	Births     int64  `json:"births"`
+	Deaths     int64  `json:"deaths"`
+	MoveIns    int64  `json:"move_ins"`
+	MoveOuts   int64  `json:"move_outs"`
+	Expected   int64  `json:"expected"`
+	Closing    int64  `json:"closing"`
+	Difference int64  `json:"difference"`
+	Reconciled bool   `json:"reconciled"`
+}
+
+type MutationRecap struct {
+	RegionCode   string            `json:"region_code"`
+	RegionName   string            `json:"region_name"`
+	PeriodStart  time.Time         `json:"period_start"`
+	PeriodEnd    time.Time         `json:"period_end"`
+	Rows         []MutationBalance `json:"rows"`
+	Total        MutationBalance   `json:"total"`
+	Unreconciled int               `json:"unreconciled"`
+}
+
+type RecapRepository interface {
+	CountResidents(c context.Context, query RecapQuery, dimension string) (rows []RecapRow, err error)
+	CountHouseholds(c context.Context, query RecapQuery) (rows []RecapRow, err error)
+	CountMutations(c context.Context, query RecapQuery, from time.Time, to time.Time) (rows []MutationRow, err error)
+}
+
+type RecapUsecase interface {
+	Population(c context.Context, filter RecapFilter) (recap PopulationRecap, err error)
+	MonthlyMutation(c context.Context, filter MutationRecapFilter) (recap MutationRecap, err error)
+	// PopulationReport menyusun laporan piramida penduduk (RecapReportAgeGroups)
+	// atau jumlah penduduk per wilayah (RecapReportRegions) untuk diekspor.
+	PopulationReport(c context.Context, filter RecapFilter, report string) (RecapReport, error)
+	// MutationReport menyusun laporan neraca mutasi bulanan untuk diekspor.
+	MutationReport(c context.Context, filter MutationRecapFilter) (RecapReport, error)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+const (
+	RecapReportAgeGroups = "age_groups"
+	RecapReportRegions   = "regions"
+	RecapReportMutations = "mutations"
+)
+
+// ReportTable adalah satu tabel laporan yang sudah berbentuk teks sehingga
+// dapat ditulis ke format berkas apa pun. Footer berisi baris jumlah.
+type ReportTable struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+	Footer []string
+}
+
+// RecapReport adalah isi laporan rekap yang siap diekspor. Kecamatan dipakai
+// pada kop laporan dan blok tanda tangan camat.
+type RecapReport struct {
+	Title      string
+	Kecamatan  string
+	RegionName string
+	Period     string
+	Tables     []ReportTable
+}
+
+// ReportSignature adalah blok tanda tangan pada laporan cetak.
+type ReportSignature struct {
+	City     string
+	Date     time.Time
+	Position string
+	Name     string
+	NIP      string
+}
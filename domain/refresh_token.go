@@ -10,20 +10,36 @@ const (
 	RefreshTokenTable = "refresh_tokens"
 )
 
+// RefreshToken hanya dapat dipakai sekali. SessionID sama untuk seluruh
+// access dan refresh token hasil rotasi dari satu kali login di satu
+// perangkat, PairTokenID berisi ID (jti) access token yang diterbitkan
+// bersamanya dan RotatedAt terisi saat token ditukar dengan pasangan token
+// baru.
 type RefreshToken struct {
-	ID        uuid.UUID `gorm:"primaryKey" json:"id"`
-	Token     string    `gorm:"type:longtext" json:"token"`
-	PairToken string    `gorm:"index" json:"pair_token"`
-	UserID    uuid.UUID `gorm:"type:char(36);not null;index;foreignKey:ID" json:"user_id"`
-	Revoked   bool      `gorm:"default:false" json:"revoked"`
-	CreatedAt int64     `gorm:"autoCreateTime" json:"created_at"`
-	ExpiresAt int64     `gorm:"index" json:"expires_at"`
+	ID          uuid.UUID `gorm:"primaryKey" json:"id"`
+	Token       string    `gorm:"type:longtext" json:"token"`
+	PairTokenID string    `gorm:"size:64;index" json:"pair_token_id"`
+	UserID      uuid.UUID `gorm:"type:char(36);not null;index;foreignKey:ID" json:"user_id"`
+	SessionID   uuid.UUID `gorm:"type:char(36);index" json:"session_id"`
+	Revoked     bool      `gorm:"default:false" json:"revoked"`
+	RotatedAt   int64     `json:"rotated_at"`
+	CreatedAt   int64     `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt   int64     `gorm:"index" json:"expires_at"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
 type RefreshTokenRepository interface {
 	Create(c context.Context, refreshToken RefreshToken) error
+	GetByToken(c context.Context, token string) (refreshToken RefreshToken, err error)
 	Revoke(c context.Context, token string) error
-	RevokeByPairToken(c context.Context, pairToken string) error
+	// Rotate menandai token sudah ditukar. Token yang sudah dicabut atau
+	// ditukar sebelumnya menghasilkan error.
+	Rotate(c context.Context, token string) error
+	RevokeBySessionID(c context.Context, sessionID uuid.UUID) error
+	RevokeByPairTokenID(c context.Context, pairTokenID string) error
 	RevokeByUserID(c context.Context, userID uuid.UUID) error
 	IsValid(c context.Context, token string) bool
 	Delete(c context.Context, token string) error
@@ -32,8 +48,13 @@ type RefreshTokenRepository interface {
 
 type RefreshTokenUsecase interface {
 	Create(c context.Context, refreshToken RefreshToken) error
+	GetByToken(c context.Context, token string) (refreshToken RefreshToken, err error)
 	Revoke(c context.Context, token string) error
-	RevokeByPairToken(c context.Context, pairToken string) error
+	// Rotate menandai token sudah ditukar. Token yang sudah dicabut atau
+	// ditukar sebelumnya menghasilkan error.
+	Rotate(c context.Context, token string) error
+	RevokeBySessionID(c context.Context, sessionID uuid.UUID) error
+	RevokeByPairTokenID(c context.Context, pairTokenID string) error
 	RevokeByUserID(c context.Context, userID uuid.UUID) error
 	IsValid(c context.Context, token string) bool
 	Delete(c context.Context, token string) error
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RegionTable = "regions"
+
+	RegionLevelKecamatan = "kecamatan"
+	RegionLevelDesa      = "desa"
+	RegionLevelDusun     = "dusun"
+
+	RegionTypeKecamatan = "kecamatan"
+	RegionTypeDesa      = "desa"
+	RegionTypeKelurahan = "kelurahan"
+	RegionTypeDusun     = "dusun"
+	RegionTypeBanjar    = "banjar"
+)
+
+// Region adalah satu wilayah administrasi di Kecamatan Bangli. Code mengikuti
+// format kode wilayah Kemendagri (51.06.02 untuk kecamatan, 51.06.02.2001 untuk
+// desa/kelurahan) dan dusun/banjar menambahkan dua digit nomor urut di
+// belakang kode desanya (51.06.02.2001.01), sehingga seluruh turunan sebuah
+// wilayah selalu berbagi prefix kode yang sama.
+type Region struct {
+	ID        uuid.UUID  `gorm:"primaryKey;type:char(36);index:idx_regions_name_sort,priority:2" json:"id"`
+	ParentID  *uuid.UUID `gorm:"type:char(36);index" json:"parent_id"`
+	Level     string     `gorm:"size:16;index" json:"level" validate:"required,oneof=kecamatan desa dusun"`
+	Type      string     `gorm:"size:16;index" json:"type" validate:"required,oneof=kecamatan desa kelurahan dusun banjar"`
+	Code      string     `gorm:"unique;size:32" json:"code" validate:"required,max=32"`
+	BpsCode   string     `gorm:"size:16;index" json:"bps_code" validate:"omitempty,numeric,max=16"`
+	Name      string     `gorm:"size:255;index:idx_regions_name_sort,priority:1" json:"name" validate:"required"`
+	CreatedAt int64      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt int64      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// regionLevelDepth mengurutkan level wilayah dari yang tertinggi.
+var regionLevelDepth = map[string]int{
+	RegionLevelKecamatan: 1,
+	RegionLevelDesa:      2,
+	RegionLevelDusun:     3,
+}
+
+// regionCodeLength adalah panjang kode wilayah untuk setiap level, misalnya
+// 51.06.02 (8), 51.06.02.2001 (13) dan 51.06.02.2001.01 (16).
+var regionCodeLength = map[string]int{
+	RegionLevelKecamatan: 8,
+	RegionLevelDesa:      13,
+	RegionLevelDusun:     16,
+}
+
+// regionCodeFormats adalah format kode wilayah untuk setiap level. Rekap
+// mengelompokkan wilayah dengan memotong kode sepanjang RegionCodeLength,
+// sehingga kode yang tidak sesuai format akan salah dikelompokkan.
+var regionCodeFormats = map[string]*regexp.Regexp{
+	RegionLevelKecamatan: regexp.MustCompile(`^\d{2}\.\d{2}\.\d{2}$`),
+	RegionLevelDesa:      regexp.MustCompile(`^\d{2}\.\d{2}\.\d{2}\.\d{4}$`),
+	RegionLevelDusun:     regexp.MustCompile(`^\d{2}\.\d{2}\.\d{2}\.\d{4}\.\d{2}$`),
+}
+
+// regionLevelTypes adalah jenis wilayah yang sah untuk setiap level.
+var regionLevelTypes = map[string][]string{
+	RegionLevelKecamatan: {RegionTypeKecamatan},
+	RegionLevelDesa:      {RegionTypeDesa, RegionTypeKelurahan},
+	RegionLevelDusun:     {RegionTypeDusun, RegionTypeBanjar},
+}
+
+// RegionLevelDepth mengembalikan kedalaman level wilayah (1 untuk kecamatan)
+// atau 0 jika level tidak dikenal.
+func RegionLevelDepth(level string) int {
+	return regionLevelDepth[level]
+}
+
+// RegionCodeLength mengembalikan panjang kode wilayah pada level tersebut
+// sehingga kode turunan dapat dipotong menjadi kode leluhurnya.
+func RegionCodeLength(level string) int {
+	return regionCodeLength[level]
+}
+
+// IsValidRegionCode memeriksa apakah kode wilayah sesuai format levelnya,
+// misalnya 51.06.02.2001 untuk desa/kelurahan.
+func IsValidRegionCode(level string, code string) bool {
+	format, ok := regionCodeFormats[level]
+	return ok && format.MatchString(code)
+}
+
+// ChildRegionLevel mengembalikan level tepat di bawah level yang diberikan.
+// Level kosong berarti di atas kecamatan dan dusun tidak memiliki turunan.
+func ChildRegionLevel(level string) string {
+	switch level {
+	case "":
+		return RegionLevelKecamatan
+	case RegionLevelKecamatan:
+		return RegionLevelDesa
+	default:
+		return RegionLevelDusun
+	}
+}
+
+// IsValidRegionType memeriksa apakah jenis wilayah boleh dipakai pada level tersebut.
+func IsValidRegionType(level string, regionType string) bool {
+	for _, t := range regionLevelTypes[level] {
+		if t == regionType {
+			return true
+		}
+	}
+	return false
+}
+
+type RegionRepository interface {
+	Create(c context.Context, region Region) error
+	Retrieve(c context.Context, filter Filter) (regions []Region, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (region Region, err error)
+	GetByCode(c context.Context, code string) (region Region, err error)
+	GetByCodes(c context.Context, codes []string) (regions []Region, err error)
+	GetChildren(c context.Context, parentID uuid.UUID) (regions []Region, err error)
+	// Update gagal bila kode wilayah yang masih memiliki wilayah turunan
+	// diubah, karena kode turunan harus diawali kode induknya.
+	Update(c context.Context, id uuid.UUID, data Region) (region Region, err error)
+	Delete(c context.Context, id uuid.UUID) error
+}
+
+type RegionUsecase interface {
+	Create(c context.Context, region Region) error
+	Retrieve(c context.Context, filter Filter) (regions []Region, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (region Region, err error)
+	GetByCode(c context.Context, code string) (region Region, err error)
+	GetByCodes(c context.Context, codes []string) (regions []Region, err error)
+	GetChildren(c context.Context, parentID uuid.UUID) (regions []Region, err error)
+	Update(c context.Context, id uuid.UUID, data Region) (region Region, err error)
+	Delete(c context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ResidentTable        = "residents"
+	ResidencePeriodTable = "residence_periods"
+
+	SexMale   = "male"
+	SexFemale = "female"
+
+	ReligionIslam       = "islam"
+	ReligionKristen     = "kristen"
+	ReligionKatolik     = "katolik"
+	ReligionHindu       = "hindu"
+	ReligionBuddha      = "buddha"
+	ReligionKonghucu    = "konghucu"
+	ReligionKepercayaan = "kepercayaan"
+
+	MaritalStatusBelumKawin = "belum_kawin"
+	MaritalStatusKawin      = "kawin"
+	MaritalStatusCeraiHidup = "cerai_hidup"
+	MaritalStatusCeraiMati  = "cerai_mati"
+
+	EducationTidakSekolah = "tidak_sekolah"
+	EducationBelumTamatSD = "belum_tamat_sd"
+	EducationSD           = "sd"
+	EducationSLTP         = "sltp"
+	EducationSLTA         = "slta"
+	EducationD1D2         = "d1_d2"
+	EducationD3           = "d3"
+	EducationD4S1         = "d4_s1"
+	EducationS2           = "s2"
+	EducationS3           = "s3"
+
+	BloodTypeA       = "A"
+	BloodTypeB       = "B"
+	BloodTypeAB      = "AB"
+	BloodTypeO       = "O"
+	BloodTypeUnknown = "unknown"
+
+	ResidentStatusActive   = "active"
+	ResidentStatusDeceased = "deceased"
+	ResidentStatusMovedOut = "moved_out"
+)
+
+// Resident adalah data penduduk sesuai isian biodata pada Kartu Keluarga.
+// RegionID menunjuk wilayah terendah tempat penduduk terdaftar (desa/kelurahan
+// atau dusun/banjar). Penduduk yang meninggal tidak dihapus melainkan ditandai
+// dengan Status dan DeathDate agar rekap bulan-bulan sebelumnya tetap
+// menghitungnya. Hal yang sama berlaku untuk penduduk yang datang dari atau
+// pindah ke luar Bangli melalui ArrivalDate dan DepartureDate.
+type Resident struct {
+	ID            uuid.UUID      `gorm:"primaryKey;type:char(36);index:idx_residents_name_sort,priority:2;index:idx_residents_birth_date_sort,priority:2;index:idx_residents_created_at_sort,priority:2;index:idx_residents_updated_at_sort,priority:2" json:"id"`
+	NIK           string         `gorm:"unique;size:16" json:"nik" validate:"required,nik,nik_sex=Sex,nik_birth_date=BirthDate"`
+	Name          string         `gorm:"size:255;index:idx_residents_name_fulltext,class:FULLTEXT;index:idx_residents_name_sort,priority:1" json:"name" validate:"required,max=255"`
+	BirthPlace    string         `gorm:"size:128" json:"birth_place" validate:"required,max=128"`
+	BirthDate     time.Time      `gorm:"type:date;index:idx_residents_birth_date_sort,priority:1" json:"birth_date" validate:"required"`
+	Sex           string         `gorm:"size:8;index" json:"sex" validate:"required,oneof=male female"`
+	Religion      string         `gorm:"size:16;index" json:"religion" validate:"required,oneof=islam kristen katolik hindu buddha konghucu kepercayaan"`
+	MaritalStatus string         `gorm:"size:16;index" json:"marital_status" validate:"required,oneof=belum_kawin kawin cerai_hidup cerai_mati"`
+	Education     string         `gorm:"size:16;index" json:"education" validate:"required,oneof=tidak_sekolah belum_tamat_sd sd sltp slta d1_d2 d3 d4_s1 s2 s3"`
+	Occupation    string         `gorm:"size:64;index" json:"occupation" validate:"required,max=64"`
+	BloodType     string         `gorm:"size:8" json:"blood_type" validate:"omitempty,oneof=A B AB O unknown"`
+	FatherName    string         `gorm:"size:255" json:"father_name" validate:"max=255"`
+	MotherName    string         `gorm:"size:255" json:"mother_name" validate:"max=255"`
+	RegionID      uuid.UUID      `gorm:"type:char(36);not null;index" json:"region_id" validate:"required"`
+	Status        string         `gorm:"size:16;index;default:active" json:"status"`
+	DeathDate     *time.Time     `gorm:"type:date;index" json:"death_date"`
+	ArrivalDate   *time.Time     `gorm:"type:date;index" json:"arrival_date"`
+	DepartureDate *time.Time     `gorm:"type:date;index" json:"departure_date"`
+	CreatedAt     int64          `gorm:"autoCreateTime;index:idx_residents_created_at_sort,priority:1" json:"created_at"`
+	UpdatedAt     int64          `gorm:"autoUpdateTime;index:idx_residents_updated_at_sort,priority:1" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ResidencePeriod adalah masa seorang penduduk tercatat di suatu wilayah,
+// berlaku mulai ValidFrom sampai sebelum ValidUntil. ValidUntil kosong berarti
+// masih berlaku. Rekap per tanggal disusun dari masa tinggal ini sehingga
+// kelahiran, kematian dan pindah/datang tidak mengubah angka bulan-bulan
+// sebelumnya.
+type ResidencePeriod struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:char(36)" json:"id"`
+	ResidentID uuid.UUID  `gorm:"type:char(36);not null;index" json:"resident_id"`
+	RegionID   uuid.UUID  `gorm:"type:char(36);not null;index" json:"region_id"`
+	ValidFrom  time.Time  `gorm:"type:date;index" json:"valid_from"`
+	ValidUntil *time.Time `gorm:"type:date;index" json:"valid_until"`
+	CreatedAt  int64      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  int64      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ResidenceStart adalah tanggal awal masa tinggal pertama penduduk: tanggal
+// datang bagi pendatang, selain itu tanggal lahir.
+func (r Resident) ResidenceStart() time.Time {
+	if r.ArrivalDate != nil {
+		return *r.ArrivalDate
+	}
+	return r.BirthDate
+}
+
+type ResidentRepository interface {
+	Create(c context.Context, resident Resident) error
+	Retrieve(c context.Context, filter Filter) (residents []Resident, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (resident Resident, err error)
+	GetByNIK(c context.Context, nik string) (resident Resident, err error)
+	GetPeriods(c context.Context, residentID uuid.UUID) (periods []ResidencePeriod, err error)
+	Update(c context.Context, id uuid.UUID, data Resident) (resident Resident, err error)
+	Delete(c context.Context, id uuid.UUID) error
+	// Restore mengembalikan penduduk yang sudah dihapus.
+	Restore(c context.Context, id uuid.UUID) error
+	// Purge menghapus permanen penduduk yang dihapus sebelum waktu tersebut.
+	Purge(c context.Context, before time.Time) (int64, error)
+}
+
+type ResidentUsecase interface {
+	Create(c context.Context, resident Resident) error
+	Retrieve(c context.Context, filter Filter) (residents []Resident, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (resident Resident, err error)
+	GetByNIK(c context.Context, nik string) (resident Resident, err error)
+	GetPeriods(c context.Context, residentID uuid.UUID) (periods []ResidencePeriod, err error)
+	Update(c context.Context, id uuid.UUID, data Resident) (resident Resident, err error)
+	Delete(c context.Context, id uuid.UUID) error
+	Restore(c context.Context, id uuid.UUID) (Resident, error)
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ResidentDuplicateTable = "resident_duplicates"
+
+	ResidentDuplicateStatusPending   = "pending"
+	ResidentDuplicateStatusMerged    = "merged"
+	ResidentDuplicateStatusDismissed = "dismissed"
+
+	// Alasan kecocokan yang dicatat pada Reasons.
+	DuplicateReasonNIK       = "nik"
+	DuplicateReasonName      = "name"
+	DuplicateReasonBirthDate = "birth_date"
+	DuplicateReasonParents   = "parents"
+)
+
+// ErrDuplicateFamilyConflict dikembalikan bila kedua penduduk masih tercatat
+// pada KK yang berbeda sehingga keanggotaannya tidak dapat digabung.
+var ErrDuplicateFamilyConflict = errors.New("both residents are current members of different families, move one of them first")
+
+// ResidentDuplicate adalah pasangan penduduk yang diduga orang yang sama,
+// ditemukan oleh pemeriksaan duplikat terjadwal dan menunggu ditinjau
+// super_admin. ResidentID selalu lebih kecil dari DuplicateID agar satu
+// pasangan hanya tercatat sekali. Setelah digabung KeptID adalah penduduk yang
+// dipertahankan, sedangkan yang lain dihapus. Resident dan Duplicate tidak
+// memakai foreign key karena riwayat penggabungan tetap disimpan setelah
+// penduduk yang dihapus dihapus permanen.
+type ResidentDuplicate struct {
+	ID          uuid.UUID  `gorm:"primaryKey;type:char(36);index:idx_resident_duplicates_score_sort,priority:2;index:idx_resident_duplicates_created_at_sort,priority:2;index:idx_resident_duplicates_updated_at_sort,priority:2" json:"id"`
+	ResidentID  uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex:idx_resident_duplicates_pair" json:"resident_id"`
+	DuplicateID uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex:idx_resident_duplicates_pair;index" json:"duplicate_id"`
+	Score       float64    `gorm:"not null;index:idx_resident_duplicates_score_sort,priority:1" json:"score"`
+	Reasons     string     `gorm:"size:64" json:"reasons"`
+	Status      string     `gorm:"size:16;index;default:pending" json:"status"`
+	KeptID      *uuid.UUID `gorm:"type:char(36)" json:"kept_id"`
+	ReviewedBy  string     `gorm:"size:36" json:"reviewed_by"`
+	ReviewedAt  int64      `json:"reviewed_at"`
+	Resident    *Resident  `gorm:"foreignKey:ResidentID;constraint:-" json:"resident,omitempty"`
+	Duplicate   *Resident  `gorm:"foreignKey:DuplicateID;constraint:-" json:"duplicate,omitempty"`
+	CreatedAt   int64      `gorm:"autoCreateTime;index:idx_resident_duplicates_created_at_sort,priority:1" json:"created_at"`
+	UpdatedAt   int64      `gorm:"autoUpdateTime;index:idx_resident_duplicates_updated_at_sort,priority:1" json:"updated_at"`
+}
+
+// RemovedID adalah penduduk pada pasangan yang tidak dipertahankan saat
+// digabung.
+func (d ResidentDuplicate) RemovedID() uuid.UUID {
+	if d.KeptID != nil && *d.KeptID == d.ResidentID {
+		return d.DuplicateID
+	}
+	return d.ResidentID
+}
+
+// ResidentDuplicateMerge memilih penduduk yang dipertahankan saat pasangan
+// digabung, harus salah satu dari kedua penduduk pada pasangan tersebut.
+type ResidentDuplicateMerge struct {
+	KeepID uuid.UUID `json:"keep_id" form:"keep_id" validate:"required"`
+}
+
+type ResidentDuplicateRepository interface {
+	Retrieve(c context.Context, filter Filter) (duplicates []ResidentDuplicate, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (duplicate ResidentDuplicate, err error)
+	// Candidates mengembalikan seluruh penduduk aktif dengan kolom yang
+	// dipakai pemeriksaan duplikat saja.
+	Candidates(c context.Context) (residents []Resident, err error)
+	// Record menyimpan pasangan baru atau memperbarui nilai pasangan yang
+	// masih menunggu tinjauan. Pasangan yang sudah ditinjau tidak diubah.
+	Record(c context.Context, duplicate ResidentDuplicate) error
+	// Merge memindahkan keanggotaan KK, kepala keluarga, peristiwa
+	// kependudukan dan masa tinggal penduduk yang tidak dipertahankan ke
+	// duplicate.KeptID, menghapus data penduduk tersebut, menyimpan duplicate
+	// sebagai riwayat penggabungan dan membuang pasangan lain yang masih
+	// menunggu tinjauan untuk penduduk tersebut, dalam satu transaksi. Bagian
+	// masa tinggal yang tumpang tindih dengan masa tinggal penduduk yang
+	// dipertahankan dibuang.
+	// Keanggotaan yang masih berlaku pada KK yang sama dengan penduduk yang
+	// dipertahankan diakhiri per tanggal date. ErrDuplicateFamilyConflict
+	// dikembalikan bila keduanya masih tercatat pada KK yang berbeda.
+	Merge(c context.Context, duplicate ResidentDuplicate, date time.Time) error
+	// Dismiss menandai pasangan bukan duplikat sehingga tidak diusulkan lagi.
+	Dismiss(c context.Context, duplicate ResidentDuplicate) error
+}
+
+type ResidentDuplicateUsecase interface {
+	Retrieve(c context.Context, filter Filter) (duplicates []ResidentDuplicate, meta MetaResponse, err error)
+	GetById(c context.Context, id uuid.UUID) (duplicate ResidentDuplicate, err error)
+	// Detect memeriksa seluruh penduduk aktif dan mencatat pasangan yang
+	// diduga duplikat, mengembalikan jumlah pasangan yang ditemukan.
+	Detect(c context.Context) (int, error)
+	Merge(c context.Context, id uuid.UUID, merge ResidentDuplicateMerge) (ResidentDuplicate, error)
+	Dismiss(c context.Context, id uuid.UUID) (ResidentDuplicate, error)
+}
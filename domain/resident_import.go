@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	// ImportBatchSize adalah jumlah baris yang disimpan dalam satu transaksi.
+	ImportBatchSize = 500
+)
+
+// ResidentImportColumns adalah urutan kolom berkas impor penduduk. Kolom
+// kk_number sampai rw boleh kosong bila penduduk belum dimasukkan ke KK.
+var ResidentImportColumns = []string{
+	"nik", "name", "birth_place", "birth_date", "sex", "religion", "marital_status",
+	"education", "occupation", "blood_type", "father_name", "mother_name", "region_code",
+	"kk_number", "relationship", "address", "rt", "rw",
+}
+
+// ResidentImportRow adalah satu baris berkas impor setelah dibaca. Row adalah
+// nomor baris pada berkas (header adalah baris 1). NIK hanya diperiksa
+// formatnya karena penduduk yang tinggal di Bangli boleh masih memakai NIK
+// dari daerah asalnya.
+type ResidentImportRow struct {
+	Row           int       `json:"row" validate:"-"`
+	NIK           string    `json:"nik" validate:"required,nik_format,nik_sex=Sex,nik_birth_date=BirthDate"`
+	Name          string    `json:"name" validate:"required,max=255"`
+	BirthPlace    string    `json:"birth_place" validate:"required,max=128"`
+	BirthDate     time.Time `json:"birth_date" validate:"required"`
+	Sex           string    `json:"sex" validate:"required,oneof=male female"`
+	Religion      string    `json:"religion" validate:"required,oneof=islam kristen katolik hindu buddha konghucu kepercayaan"`
+	MaritalStatus string    `json:"marital_status" validate:"required,oneof=belum_kawin kawin cerai_hidup cerai_mati"`
+	Education     string    `json:"education" validate:"required,oneof=tidak_sekolah belum_tamat_sd sd sltp slta d1_d2 d3 d4_s1 s2 s3"`
+	Occupation    string    `json:"occupation" validate:"required,max=64"`
+	BloodType     string    `json:"blood_type" validate:"omitempty,oneof=A B AB O unknown"`
+	FatherName    string    `json:"father_name" validate:"max=255"`
+	MotherName    string    `json:"mother_name" validate:"max=255"`
+	RegionCode    string    `json:"region_code" validate:"required,max=32"`
+	KKNumber      string    `json:"kk_number" validate:"omitempty,kk"`
+	Relationship  string    `json:"relationship" validate:"required_with=KKNumber,omitempty,oneof=kepala_keluarga suami istri anak menantu cucu orang_tua mertua famili_lain pembantu lainnya"`
+	Address       string    `json:"address" validate:"max=255"`
+	RT            string    `json:"rt" validate:"omitempty,numeric,max=3"`
+	RW            string    `json:"rw" validate:"omitempty,numeric,max=3"`
+}
+
+// ResidentImportRecord adalah baris valid yang siap disimpan. Family hanya
+// diisi bila baris mencantumkan nomor KK.
+type ResidentImportRecord struct {
+	Row          int
+	Resident     Resident
+	Family       *Family
+	Relationship string
+}
+
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	NIK     string `json:"nik"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+type ImportResult struct {
+	TotalRows int              `json:"total_rows"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Failed    int              `json:"failed"`
+	Errors    []ImportRowError `json:"errors"`
+}
+
+type ResidentImportRepository interface {
+	// ImportBatch menyimpan (insert atau update berdasarkan NIK) seluruh
+	// record beserta KK dan keanggotaannya dalam satu transaksi. Bila satu
+	// record gagal seluruh batch dibatalkan.
+	ImportBatch(c context.Context, records []ResidentImportRecord) (created int, updated int, err error)
+}
+
+type ResidentImportUsecase interface {
+	// Import membaca berkas CSV atau XLSX. Baris yang tidak valid dicatat pada
+	// ImportResult.Errors tanpa menghentikan impor baris lainnya.
+	Import(c context.Context, format string, file io.Reader) (ImportResult, error)
+	// WriteReport menulis laporan kesalahan per baris sebagai CSV atau XLSX.
+	WriteReport(format string, result ImportResult, w io.Writer) error
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "context"
+
+// ResidentSearchQuery adalah pencarian cepat penduduk di loket. Query boleh
+// berisi sebagian nama dengan ejaan singkat ("Ni Wyn Sari"), awalan NIK atau
+// nomor KK, maupun sebagian alamat.
+type ResidentSearchQuery struct {
+	Query string `json:"q" form:"q" validate:"required,min=2,max=255"`
+	Limit int    `json:"limit" form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// ResidentSearchResult adalah penduduk hasil pencarian beserta KK yang masih
+// berlaku dan nilai kecocokannya antara 0 dan 1.
+type ResidentSearchResult struct {
+	Resident
+	KKNumber string  `json:"kk_number"`
+	Address  string  `json:"address"`
+	Score    float64 `json:"score"`
+}
+
+// ResidentSearchRepository mencari calon penduduk dalam wilayah pengguna.
+// Terms adalah kata yang sudah dinormalisasi dan hanya berisi huruf dan
+// angka, limit adalah jumlah calon yang diambil sebelum diberi peringkat.
+type ResidentSearchRepository interface {
+	// SearchName mencari lewat indeks FULLTEXT nama penduduk.
+	SearchName(c context.Context, terms []string, limit int) (results []ResidentSearchResult, err error)
+	// SearchAddress mencari lewat indeks FULLTEXT alamat KK.
+	SearchAddress(c context.Context, terms []string, limit int) (results []ResidentSearchResult, err error)
+	// SearchNumber mencari penduduk dengan awalan NIK atau nomor KK.
+	SearchNumber(c context.Context, prefix string, limit int) (results []ResidentSearchResult, err error)
+}
+
+type ResidentSearchUsecase interface {
+	Search(c context.Context, query ResidentSearchQuery) (results []ResidentSearchResult, err error)
+}
@@ -3,22 +3,52 @@ package domain
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 const (
 	UserTable = "users"
+
+	RoleSuperAdmin   = "super_admin"
+	RoleAdmin        = "admin"
+	RoleStaff        = "staff"
+	RoleOperatorDesa = "operator_desa"
+	// DefaultRole diberikan kepada pengguna yang mendaftar sendiri sampai
+	// super_admin menetapkan peran lain.
+	DefaultRole = RoleStaff
 )
 
+// UserRoles adalah seluruh peran yang dapat diberikan kepada pengguna.
+var UserRoles = []string{RoleSuperAdmin, RoleAdmin, RoleStaff, RoleOperatorDesa}
+
+// User yang mendaftar sendiri aktif setelah emailnya diverifikasi.
+// EmailVerifiedAt tetap terisi walaupun akun dinonaktifkan kemudian sehingga
+// tautan verifikasi tidak dapat dipakai untuk mengaktifkan ulang akun. Email
+// pengguna yang dihapus diberi akhiran DeletedUserEmail agar dapat dipakai
+// mendaftar lagi, akhiran tersebut dibuang saat pengguna dipulihkan.
 type User struct {
-	ID       uuid.UUID `gorm:"primaryKey;type:char(36)" json:"id"`
-	Name     string    `gorm:"size:255;index" json:"name"`
-	Email    string    `gorm:"unique;size:255;index" json:"email"`
-	Password string    `json:"-"`
-	IsActive bool      `gorm:"index" json:"is_active"`
-	Role     string    `gorm:"size:16;index" json:"role"`
+	ID              uuid.UUID      `gorm:"primaryKey;type:char(36);index:idx_users_name_sort,priority:2;index:idx_users_role_sort,priority:2" json:"id"`
+	Name            string         `gorm:"size:255;index:idx_users_name_sort,priority:1" json:"name"`
+	Email           string         `gorm:"unique;size:320;index" json:"email"`
+	Password        string         `json:"-"`
+	IsActive        bool           `gorm:"index" json:"is_active"`
+	Role            string         `gorm:"size:16;index:idx_users_role_sort,priority:1" json:"role"`
+	EmailVerifiedAt int64          `json:"email_verified_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// DeletedEmailSeparator memisahkan email asli dari ID pengguna pada email
+// pengguna yang dihapus.
+const DeletedEmailSeparator = "#deleted-"
+
+// DeletedUserEmail adalah email pengguna yang sedang dihapus sehingga email
+// aslinya tidak lagi terpakai oleh unique index.
+func DeletedUserEmail(id uuid.UUID, email string) string {
+	return email + DeletedEmailSeparator + id.String()
 }
 
 type RegisterUser struct {
@@ -43,6 +73,10 @@ type ResetPassword struct {
 	Token      string `json:"token" validate:"required"`
 }
 
+type UpdateUserRole struct {
+	Role string `json:"role" form:"role" validate:"required,oneof=super_admin admin staff operator_desa"`
+}
+
 type UserTokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -69,7 +103,7 @@ func (ru *RegisterUser) ToUser() (user User, err error) {
 	user.Email = ru.Email
 	user.Password = string(encryptedPassword)
 	user.IsActive = false
-	user.Role = "admin"
+	user.Role = DefaultRole
 	return
 }
 
@@ -80,7 +114,22 @@ type UserRepository interface {
 	GetById(c context.Context, id uuid.UUID) (user User, err error)
 	Update(c context.Context, id uuid.UUID, data User) (user User, err error)
 	UpdatePassword(c context.Context, id uuid.UUID, newPasswordHash string) (err error)
+	VerifyEmail(c context.Context, id uuid.UUID) (err error)
+	UpdateStatus(c context.Context, id uuid.UUID, isActive bool) (err error)
+	UpdateRole(c context.Context, id uuid.UUID, role string) (err error)
+	// GetRegions mengembalikan wilayah tugas pengguna.
+	GetRegions(c context.Context, id uuid.UUID) (regions []Region, err error)
+	// GetAssignments mengembalikan wilayah tugas beberapa pengguna sekaligus
+	// beserta wilayahnya, urut kode wilayah.
+	GetAssignments(c context.Context, ids []uuid.UUID) (assignments []UserRegion, err error)
+	// SetRegions mengganti seluruh wilayah tugas pengguna.
+	SetRegions(c context.Context, id uuid.UUID, regionIDs []uuid.UUID) (err error)
 	Delete(c context.Context, id uuid.UUID) error
+	// Restore mengembalikan pengguna yang sudah dihapus beserta email
+	// aslinya, gagal bila email tersebut sudah dipakai pengguna lain.
+	Restore(c context.Context, id uuid.UUID) error
+	// Purge menghapus permanen pengguna yang dihapus sebelum waktu tersebut.
+	Purge(c context.Context, before time.Time) (int64, error)
 }
 
 type UserUsecase interface {
@@ -90,5 +139,16 @@ type UserUsecase interface {
 	GetById(c context.Context, id uuid.UUID) (user User, err error)
 	Update(c context.Context, id uuid.UUID, data User) (user User, err error)
 	UpdatePassword(c context.Context, id uuid.UUID, newPasswordHash string) (err error)
+	VerifyEmail(c context.Context, id uuid.UUID) (err error)
+	UpdateStatus(c context.Context, id uuid.UUID, isActive bool) (err error)
+	UpdateRole(c context.Context, id uuid.UUID, role string) (err error)
+	// GetRegions mengembalikan wilayah tugas pengguna.
+	GetRegions(c context.Context, id uuid.UUID) (regions []Region, err error)
+	// GetAssignments mengembalikan wilayah tugas beberapa pengguna sekaligus
+	// beserta wilayahnya, urut kode wilayah.
+	GetAssignments(c context.Context, ids []uuid.UUID) (assignments []UserRegion, err error)
+	// SetRegions mengganti seluruh wilayah tugas pengguna.
+	SetRegions(c context.Context, id uuid.UUID, regionIDs []uuid.UUID) (err error)
 	Delete(c context.Context, id uuid.UUID) error
+	Restore(c context.Context, id uuid.UUID) (User, error)
 }
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UserRegionTable = "user_regions"
+
+	// RegionScopeContext adalah kunci context tempat middleware menyimpan
+	// cakupan wilayah pengguna yang sedang masuk.
+	RegionScopeContext = "x-region-scope"
+)
+
+// ErrRegionOutOfScope dikembalikan bila data yang diminta berada di luar
+// wilayah tugas pengguna.
+var ErrRegionOutOfScope = errors.New("region is outside your assigned area")
+
+// UserRegion menugaskan pengguna pada satu wilayah. Pengguna hanya dapat
+// membaca dan mengubah data penduduk di wilayah tersebut beserta seluruh
+// turunannya.
+type UserRegion struct {
+	UserID    uuid.UUID `gorm:"primaryKey;type:char(36)" json:"user_id"`
+	RegionID  uuid.UUID `gorm:"primaryKey;type:char(36)" json:"region_id"`
+	Region    *Region   `gorm:"foreignKey:RegionID" json:"region,omitempty"`
+	CreatedAt int64     `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// UpdateUserRegions mengganti seluruh wilayah tugas pengguna. Daftar kosong
+// mencabut seluruh penugasan.
+type UpdateUserRegions struct {
+	RegionIDs []uuid.UUID `json:"region_ids"`
+}
+
+// RegionScope adalah cakupan wilayah pengguna berupa kode wilayah tugasnya.
+// Seluruh turunan wilayah berbagi prefix kode sehingga cakupan cukup
+// dicocokkan dengan prefix. Restricted false berarti pengguna melihat seluruh
+// wilayah, Restricted tanpa Codes berarti pengguna tidak melihat data apa pun.
+type RegionScope struct {
+	Restricted bool
+	Codes      []string
+}
+
+// NewRegionScope menentukan cakupan pengguna dari peran dan wilayah tugasnya.
+// Hanya super_admin yang tidak dibatasi, peran lain selalu dibatasi pada
+// wilayah tugasnya sehingga pengguna tanpa penugasan, termasuk peran yang
+// tidak dikenali, tidak melihat data apa pun. Staf kecamatan ditugaskan pada
+// wilayah kecamatan untuk melihat seluruh desa di dalamnya.
+func NewRegionScope(role string, regions []Region) RegionScope {
+	if role == RoleSuperAdmin {
+		return RegionScope{}
+	}
+	scope := RegionScope{Restricted: true, Codes: make([]string, 0, len(regions))}
+	for _, region := range regions {
+		scope.Codes = append(scope.Codes, region.Code)
+	}
+	return scope
+}
+
+// Allows memeriksa apakah wilayah dengan kode tersebut berada di dalam cakupan.
+func (s RegionScope) Allows(code string) bool {
+	if !s.Restricted {
+		return true
+	}
+	for _, scope := range s.Codes {
+		if code == scope || strings.HasPrefix(code, scope+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRegionScope menyimpan cakupan wilayah pada context di luar request
+// HTTP, misalnya untuk pengujian atau proses latar belakang.
+func WithRegionScope(c context.Context, scope RegionScope) context.Context {
+	return context.WithValue(c, RegionScopeContext, scope)
+}
+
+// RegionScopeFromContext mengembalikan cakupan wilayah pada context. Context
+// tanpa cakupan, misalnya proses impor dari command line dan scheduler,
+// dianggap tidak dibatasi.
+func RegionScopeFromContext(c context.Context) RegionScope {
+	scope, _ := c.Value(RegionScopeContext).(RegionScope)
+	return scope
+}
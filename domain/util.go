@@ -1,19 +1,54 @@
 package domain
 
 import (
+	"time"
+	// Data zona waktu disertakan agar TimeZone tetap dapat dimuat pada server
+	// tanpa tzdata.
+	_ "time/tzdata"
+
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 )
 
+// TimeZone adalah zona waktu Kabupaten Bangli (WITA). Koneksi database dan
+// perhitungan tanggal kependudukan memakai zona waktu yang sama agar batas
+// tanggal tidak bergeser satu hari.
+const TimeZone = "Asia/Makassar"
+
+// Location adalah lokasi untuk TimeZone.
+var Location = loadLocation()
+
+func loadLocation() *time.Location {
+	location, err := time.LoadLocation(TimeZone)
+	if err != nil {
+		return time.FixedZone("WITA", 8*60*60)
+	}
+	return location
+}
+
 type Filter struct {
-	Search         string `json:"search" query:"search"`
-	Page           int64  `json:"page" query:"page"`
-	Limit          int64  `json:"limit" query:"limit"`
-	WithPagination bool   `json:"with_pagination" query:"with_pagination"`
-	ShowDeleted    bool   `json:"show_deleted" query:"show_deleted"`
-	SortBy         string `json:"sort_by" query:"sort_by"`
-	OrderBy        string `json:"order_by" query:"order_by"`
-	UserRole       string `json:"user_role" query:"user_role"`
+	Search         string `json:"search" query:"search" form:"search"`
+	Page           int64  `json:"page" query:"page" form:"page"`
+	Limit          int64  `json:"limit" query:"limit" form:"limit"`
+	WithPagination bool   `json:"with_pagination" query:"with_pagination" form:"with_pagination"`
+	// WithCursor memakai pagination cursor (keyset) sebagai pengganti nomor
+	// halaman untuk menelusuri data yang sangat banyak. Cursor diisi dengan
+	// next_cursor atau prev_cursor dari meta sebelumnya bersama sort_by,
+	// order_by dan filter yang sama, dan sudah berarti WithCursor.
+	WithCursor  bool   `json:"with_cursor" query:"with_cursor" form:"with_cursor"`
+	Cursor      string `json:"cursor" query:"cursor" form:"cursor"`
+	ShowDeleted bool   `json:"show_deleted" query:"show_deleted" form:"show_deleted"`
+	SortBy      string `json:"sort_by" query:"sort_by" form:"sort_by"`
+	OrderBy     string `json:"order_by" query:"order_by" form:"order_by"`
+	UserRole    string `json:"user_role" query:"user_role" form:"user_role"`
+	// Filters adalah syarat per kolom berformat kolom:nilai dan boleh diulang,
+	// misalnya filter=sex:male, filter=religion:hindu,islam (salah satu) atau
+	// filter=birth_date:2000-01-01,2005-12-31 (rentang, salah satu batas boleh
+	// kosong). Kolom yang dapat disaring ditentukan oleh masing-masing data.
+	Filters []string `json:"filters" query:"filter" form:"filter"`
+	// AsOf membatasi data kependudukan pada keadaan di tanggal tersebut,
+	// kosong berarti keadaan saat ini.
+	AsOf time.Time `json:"as_of" query:"as_of" form:"as_of" time_format:"2006-01-02"`
 }
 
 type MetaResponse struct {
@@ -22,19 +57,27 @@ type MetaResponse struct {
 	Page            int64 `json:"page"`
 	PerPage         int64 `json:"per_page"`
 	TotalPages      int64 `json:"total_pages"`
+	// NextCursor dan PrevCursor hanya diisi pada pagination cursor dan kosong
+	// bila tidak ada halaman pada arah tersebut. Pada pagination cursor,
+	// TotalRecords, FilteredRecords dan TotalPages bernilai nol di halaman
+	// yang dibuka dengan Cursor.
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 type JwtCustomClaims struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Role string `json:"role"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.StandardClaims
 }
 
 type JwtCustomRefreshClaims struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Role string `json:"role"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.StandardClaims
 }
 
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VerificationEmailTokenTable = "verification_email_tokens"
+)
+
+type VerificationEmailToken struct {
+	ID        uuid.UUID `gorm:"primaryKey" json:"id"`
+	Token     string    `gorm:"type:longtext" json:"token"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index;foreignKey:ID" json:"user_id"`
+	Revoked   bool      `gorm:"default:false" json:"revoked"`
+	CreatedAt int64     `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt int64     `gorm:"index" json:"expires_at"`
+}
+
+type ResendVerificationEmail struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type VerificationEmailTokenRepository interface {
+	Create(c context.Context, verificationEmailToken VerificationEmailToken) error
+	Revoke(c context.Context, token string) error
+	RevokeByUserID(c context.Context, userID uuid.UUID) error
+	IsValid(c context.Context, token string) bool
+	GetUserID(c context.Context, token string) (userID uuid.UUID, err error)
+	Delete(c context.Context, token string) error
+	DeleteExpiredToken(c context.Context, millisDateTime int64) error
+}
+
+type VerificationEmailTokenUsecase interface {
+	Create(c context.Context, verificationEmailToken VerificationEmailToken) error
+	Revoke(c context.Context, token string) error
+	RevokeByUserID(c context.Context, userID uuid.UUID) error
+	IsValid(c context.Context, token string) bool
+	GetUserID(c context.Context, token string) (userID uuid.UUID, err error)
+	Delete(c context.Context, token string) error
+	DeleteExpiredToken(c context.Context, millisDateTime int64) error
+}
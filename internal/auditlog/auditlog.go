@@ -0,0 +1,286 @@
+package auditlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// beforeKey adalah kunci instance statement tempat isi baris sebelum update
+// atau delete disimpan sampai callback sesudahnya.
+const beforeKey = "auditlog:before"
+
+// commitCallback adalah callback GORM yang menutup transaksi bawaan, catatan
+// harus ditulis sebelumnya agar berada dalam transaksi yang sama.
+const commitCallback = "gorm:commit_or_rollback_transaction"
+
+// ignoredColumns tidak dibandingkan pada aksi update karena selalu berubah
+// tanpa mengubah isi data.
+var ignoredColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Plugin mencatat setiap create, update dan delete melalui GORM pada tabel
+// yang diaudit ke tabel audit_logs. Catatan ditulis dalam transaksi yang sama
+// sehingga ikut batal bila perubahan dibatalkan, dan kegagalan menulis
+// catatan menggagalkan perubahannya. Perubahan melalui Exec tidak tercatat.
+type Plugin struct {
+	tables map[string]bool
+}
+
+// New membuat plugin untuk tabel-tabel tersebut, dipasang dengan db.Use.
+func New(tables ...string) *Plugin {
+	p := &Plugin{tables: map[string]bool{}}
+	for _, table := range tables {
+		p.tables[table] = true
+	}
+	return p
+}
+
+func (p *Plugin) Name() string {
+	return "auditlog"
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Create().After("gorm:create").Before(commitCallback).Register("auditlog:after_create", p.afterCreate); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("auditlog:before_update", p.snapshot); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Before(commitCallback).Register("auditlog:after_update", p.afterUpdate); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("auditlog:before_delete", p.snapshot); err != nil {
+		return err
+	}
+	return callback.Delete().After("gorm:delete").Before(commitCallback).Register("auditlog:after_delete", p.afterDelete)
+}
+
+func (p *Plugin) audited(db *gorm.DB) bool {
+	return db.Error == nil && !db.DryRun && p.tables[db.Statement.Table]
+}
+
+func (p *Plugin) afterCreate(db *gorm.DB) {
+	if !p.audited(db) {
+		return
+	}
+	ids := primaryKeys(db)
+	if len(ids) == 0 {
+		return
+	}
+	rows, err := p.find(db, ids)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	logs := make([]domain.AuditLog, 0, len(rows))
+	for _, row := range rows {
+		logs = append(logs, newLog(db, domain.AuditActionCreate, row, nil, row))
+	}
+	p.write(db, logs)
+}
+
+// snapshot menyimpan isi baris yang akan diubah atau dihapus dengan syarat
+// yang sama dengan statement-nya.
+func (p *Plugin) snapshot(db *gorm.DB) {
+	if !p.audited(db) {
+		return
+	}
+	query := db.Session(&gorm.Session{NewDB: true}).Table(db.Statement.Table)
+	conditions := 0
+	if where, ok := db.Statement.Clauses["WHERE"].Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+		query = query.Clauses(where)
+		conditions++
+	}
+	if ids := primaryKeys(db); len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+		conditions++
+	}
+	if conditions == 0 {
+		return
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Find(&rows).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(beforeKey, normalize(rows))
+}
+
+func (p *Plugin) afterUpdate(db *gorm.DB) {
+	before := snapshotOf(db)
+	if !p.audited(db) || len(before) == 0 {
+		return
+	}
+	ids := make([]interface{}, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, row["id"])
+	}
+	rows, err := p.find(db, ids)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	after := make(map[string]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		after[fmt.Sprint(row["id"])] = row
+	}
+
+	logs := make([]domain.AuditLog, 0, len(before))
+	for _, row := range before {
+		changedBefore, changedAfter := Diff(row, after[fmt.Sprint(row["id"])])
+		if len(changedBefore) == 0 {
+			continue
+		}
+		logs = append(logs, newLog(db, domain.AuditActionUpdate, row, changedBefore, changedAfter))
+	}
+	p.write(db, logs)
+}
+
+func (p *Plugin) afterDelete(db *gorm.DB) {
+	before := snapshotOf(db)
+	if !p.audited(db) || db.RowsAffected == 0 || len(before) == 0 {
+		return
+	}
+	logs := make([]domain.AuditLog, 0, len(before))
+	for _, row := range before {
+		logs = append(logs, newLog(db, domain.AuditActionDelete, row, row, nil))
+	}
+	p.write(db, logs)
+}
+
+func (p *Plugin) find(db *gorm.DB, ids []interface{}) (rows []map[string]interface{}, err error) {
+	err = db.Session(&gorm.Session{NewDB: true}).Table(db.Statement.Table).Where("id IN ?", ids).Find(&rows).Error
+	return normalize(rows), err
+}
+
+func (p *Plugin) write(db *gorm.DB, logs []domain.AuditLog) {
+	if len(logs) == 0 {
+		return
+	}
+	actor := domain.AuditActorFromContext(db.Statement.Context)
+	for i := range logs {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		logs[i].ID = id
+		logs[i].ActorID = actor.UserID
+		logs[i].ActorRole = actor.Role
+		logs[i].IPAddress = actor.IPAddress
+	}
+	if err := db.Session(&gorm.Session{NewDB: true}).Table(domain.AuditLogTable).Create(&logs).Error; err != nil {
+		db.AddError(err)
+	}
+}
+
+func newLog(db *gorm.DB, action string, row map[string]interface{}, before map[string]interface{}, after map[string]interface{}) domain.AuditLog {
+	log := domain.AuditLog{
+		Entity:   db.Statement.Table,
+		EntityID: fmt.Sprint(row["id"]),
+		Action:   action,
+	}
+	var err error
+	if log.Before, err = marshal(before); err != nil {
+		db.AddError(err)
+	}
+	if log.After, err = marshal(after); err != nil {
+		db.AddError(err)
+	}
+	return log
+}
+
+// Diff membandingkan isi baris sebelum dan sesudah update, lalu mengembalikan
+// nilai lama dan nilai baru kolom yang berubah saja. Kolom waktu pencatatan
+// diabaikan.
+func Diff(before map[string]interface{}, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for column, value := range before {
+		if ignoredColumns[column] {
+			continue
+		}
+		if newValue, ok := after[column]; !ok || !reflect.DeepEqual(value, newValue) {
+			changedBefore[column] = value
+			changedAfter[column] = newValue
+		}
+	}
+	for column, value := range after {
+		if _, ok := before[column]; ok || ignoredColumns[column] {
+			continue
+		}
+		changedBefore[column] = nil
+		changedAfter[column] = value
+	}
+	return changedBefore, changedAfter
+}
+
+func marshal(values map[string]interface{}) (json.RawMessage, error) {
+	if values == nil {
+		return nil, nil
+	}
+	return json.Marshal(values)
+}
+
+// normalize mengubah nilai kolom teks yang terbaca sebagai byte menjadi
+// string agar tercatat apa adanya pada JSON.
+func normalize(rows []map[string]interface{}) []map[string]interface{} {
+	for _, row := range rows {
+		for column, value := range row {
+			if b, ok := value.([]byte); ok {
+				row[column] = string(b)
+			}
+		}
+	}
+	return rows
+}
+
+func snapshotOf(db *gorm.DB) []map[string]interface{} {
+	value, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := value.([]map[string]interface{})
+	return rows
+}
+
+// primaryKeys mengembalikan primary key yang terisi pada model statement,
+// yaitu data yang baru dibuat atau model yang diubah berdasarkan ID-nya.
+func primaryKeys(db *gorm.DB) []interface{} {
+	if db.Statement.Schema == nil || db.Statement.Schema.PrioritizedPrimaryField == nil {
+		return nil
+	}
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	value := reflect.Indirect(db.Statement.ReflectValue)
+
+	ids := []interface{}{}
+	appendID := func(item reflect.Value) {
+		item = reflect.Indirect(item)
+		if item.Kind() != reflect.Struct || item.Type() != db.Statement.Schema.ModelType {
+			return
+		}
+		if id, isZero := field.ValueOf(db.Statement.Context, item); !isZero {
+			ids = append(ids, id)
+		}
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			appendID(value.Index(i))
+		}
+	case reflect.Struct:
+		appendID(value)
+	}
+	return ids
+}
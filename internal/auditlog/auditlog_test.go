@@ -0,0 +1,27 @@
+package auditlog_test
+
+import (
+	"testing"
+
+	"github.com/koropati/population-recap/internal/auditlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	before := map[string]interface{}{"id": "a", "name": "Wayan", "address": "Jl. Raya", "updated_at": int64(1)}
+
+	// Kasus uji untuk kolom yang berubah
+	changedBefore, changedAfter := auditlog.Diff(before, map[string]interface{}{"id": "a", "name": "Wayan", "address": "Jl. Baru", "updated_at": int64(2)})
+	assert.Equal(t, map[string]interface{}{"address": "Jl. Raya"}, changedBefore)
+	assert.Equal(t, map[string]interface{}{"address": "Jl. Baru"}, changedAfter)
+
+	// Kasus uji untuk update yang hanya mengubah waktu pencatatan
+	changedBefore, changedAfter = auditlog.Diff(before, map[string]interface{}{"id": "a", "name": "Wayan", "address": "Jl. Raya", "updated_at": int64(2)})
+	assert.Empty(t, changedBefore)
+	assert.Empty(t, changedAfter)
+
+	// Kasus uji untuk kolom yang hanya ada di salah satu sisi
+	changedBefore, changedAfter = auditlog.Diff(map[string]interface{}{"id": "a", "rt": "001"}, map[string]interface{}{"id": "a", "rw": "002"})
+	assert.Equal(t, map[string]interface{}{"rt": "001", "rw": nil}, changedBefore)
+	assert.Equal(t, map[string]interface{}{"rt": nil, "rw": "002"}, changedAfter)
+}
@@ -0,0 +1,230 @@
+package balinese
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Urutan kelahiran pada nama Bali. Wayan, Putu dan Gede sama-sama anak
+// pertama sehingga dianggap setara, begitu pula nama urutan lainnya.
+const (
+	BirthOrderFirst  = 1
+	BirthOrderSecond = 2
+	BirthOrderThird  = 3
+	BirthOrderFourth = 4
+)
+
+// birthOrders memetakan nama urutan kelahiran ke urutannya.
+var birthOrders = map[string]int{
+	"wayan":  BirthOrderFirst,
+	"putu":   BirthOrderFirst,
+	"gede":   BirthOrderFirst,
+	"made":   BirthOrderSecond,
+	"kadek":  BirthOrderSecond,
+	"nengah": BirthOrderSecond,
+	"nyoman": BirthOrderThird,
+	"komang": BirthOrderThird,
+	"ketut":  BirthOrderFourth,
+}
+
+// titles adalah penanda jenis kelamin dan gelar kasta yang tidak membedakan
+// satu orang dengan yang lain.
+var titles = map[string]bool{
+	"i":       true,
+	"ni":      true,
+	"luh":     true,
+	"ida":     true,
+	"bagus":   true,
+	"ayu":     true,
+	"gusti":   true,
+	"ngurah":  true,
+	"anak":    true,
+	"agung":   true,
+	"cokorda": true,
+	"dewa":    true,
+	"desak":   true,
+	"sang":    true,
+	"jero":    true,
+}
+
+// abbreviations adalah singkatan yang biasa diketik petugas.
+var abbreviations = map[string][]string{
+	"wyn": {"wayan"},
+	"wy":  {"wayan"},
+	"pt":  {"putu"},
+	"gd":  {"gede"},
+	"md":  {"made"},
+	"kd":  {"kadek"},
+	"kdk": {"kadek"},
+	"ngh": {"nengah"},
+	"nym": {"nyoman"},
+	"nyo": {"nyoman"},
+	"km":  {"komang"},
+	"kmg": {"komang"},
+	"kt":  {"ketut"},
+	"ktt": {"ketut"},
+	"gst": {"gusti"},
+	"ngr": {"ngurah"},
+	"dw":  {"dewa"},
+	"dsk": {"desak"},
+	"cok": {"cokorda"},
+	"ib":  {"ida", "bagus"},
+	"ia":  {"ida", "ayu"},
+	"aa":  {"anak", "agung"},
+	"agk": {"anak", "agung"},
+}
+
+// Name adalah nama yang sudah dipisah menjadi gelar, urutan kelahiran dan
+// nama inti. Nama inti adalah bagian yang membedakan seseorang.
+type Name struct {
+	Titles     []string
+	BirthOrder int
+	Core       []string
+}
+
+// Tokens memecah nama menjadi kata huruf kecil tanpa tanda baca dan
+// menguraikan singkatan, misalnya "I Gst. Ngr Md" menjadi
+// [i gusti ngurah made].
+func Tokens(name string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if expanded, ok := abbreviations[field]; ok {
+			tokens = append(tokens, expanded...)
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// Parse memisahkan gelar dan nama urutan kelahiran dari nama inti. Nama
+// urutan kelahiran kedua dan seterusnya dianggap nama inti, misalnya "Ketut"
+// pada "I Made Ketut".
+func Parse(name string) Name {
+	var parsed Name
+	for _, token := range Tokens(name) {
+		if len(parsed.Core) == 0 {
+			if titles[token] {
+				parsed.Titles = append(parsed.Titles, token)
+				continue
+			}
+			if order, ok := birthOrders[token]; ok && parsed.BirthOrder == 0 {
+				parsed.BirthOrder = order
+				continue
+			}
+		}
+		parsed.Core = append(parsed.Core, token)
+	}
+	return parsed
+}
+
+// BirthOrderNames mengembalikan semua nama urutan kelahiran yang setara
+// dengan urutan tersebut, misalnya wayan, putu dan gede untuk anak pertama.
+func BirthOrderNames(order int) []string {
+	var names []string
+	for name, o := range birthOrders {
+		if o == order {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Score menilai kemiripan nama penduduk dengan nama yang dicari antara 0 dan
+// 1. Gelar diabaikan, nama inti menentukan sebagian besar nilai dan nama
+// urutan kelahiran hanya menambah nilai bila sama urutannya, sehingga
+// "Ni Wyn Sari" paling mirip dengan "Ni Wayan Sari" dan "Putu Sari", bukan
+// dengan "Ni Wayan Suci". Pencarian yang hanya berisi urutan kelahiran,
+// misalnya "Ni Wyn", cocok dengan semua nama berurutan kelahiran sama.
+func Score(query string, name string) float64 {
+	q, n := Parse(query), Parse(name)
+	if len(q.Core) == 0 {
+		if q.BirthOrder != 0 && q.BirthOrder == n.BirthOrder {
+			return 1
+		}
+		return 0
+	}
+
+	var total float64
+	for _, token := range q.Core {
+		var best float64
+		for _, candidate := range n.Core {
+			if similarity := tokenSimilarity(token, candidate); similarity > best {
+				best = similarity
+			}
+		}
+		total += best
+	}
+	score := total / float64(len(q.Core))
+	if q.BirthOrder == 0 {
+		return score
+	}
+	if q.BirthOrder == n.BirthOrder {
+		return 0.8*score + 0.2
+	}
+	return 0.8 * score
+}
+
+// Similarity menilai kemiripan dua nama yang sama-sama lengkap, misalnya
+// saat mencari data ganda, sehingga nama yang lebih panjang tidak dianggap
+// cocok hanya karena memuat nama yang lebih pendek.
+func Similarity(a string, b string) float64 {
+	forward, backward := Score(a, b), Score(b, a)
+	if backward < forward {
+		return backward
+	}
+	return forward
+}
+
+// tokenSimilarity membandingkan dua kata: sama persis bernilai 1, awalan
+// kata yang sedang diketik 0.9 dan salah ketik kecil dinilai dari jarak
+// Levenshtein-nya.
+func tokenSimilarity(query string, candidate string) float64 {
+	if query == candidate {
+		return 1
+	}
+	if strings.HasPrefix(candidate, query) {
+		return 0.9
+	}
+	longest := len([]rune(query))
+	if n := len([]rune(candidate)); n > longest {
+		longest = n
+	}
+	similarity := 1 - float64(levenshtein(query, candidate))/float64(longest)
+	if similarity < 0.6 {
+		return 0
+	}
+	return similarity * 0.8
+}
+
+func levenshtein(a string, b string) int {
+	s, t := []rune(a), []rune(b)
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(t)]
+}
@@ -0,0 +1,68 @@
+package balinese_test
+
+import (
+	"testing"
+
+	"github.com/koropati/population-recap/internal/balinese"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	// Kasus uji untuk gelar, urutan kelahiran dan nama inti
+	name := balinese.Parse("Ni Wayan Sari")
+	assert.Equal(t, []string{"ni"}, name.Titles)
+	assert.Equal(t, balinese.BirthOrderFirst, name.BirthOrder)
+	assert.Equal(t, []string{"sari"}, name.Core)
+
+	// Kasus uji untuk singkatan dan tanda baca
+	name = balinese.Parse("I Gst. Ngr Md Oka")
+	assert.Equal(t, []string{"i", "gusti", "ngurah"}, name.Titles)
+	assert.Equal(t, balinese.BirthOrderSecond, name.BirthOrder)
+	assert.Equal(t, []string{"oka"}, name.Core)
+
+	// Kasus uji untuk urutan kelahiran kedua yang menjadi nama inti
+	name = balinese.Parse("I Made Ketut")
+	assert.Equal(t, balinese.BirthOrderSecond, name.BirthOrder)
+	assert.Equal(t, []string{"ketut"}, name.Core)
+}
+
+func TestBirthOrderNames(t *testing.T) {
+	// Kasus uji untuk nama urutan kelahiran yang setara
+	assert.Equal(t, []string{"gede", "putu", "wayan"}, balinese.BirthOrderNames(balinese.BirthOrderFirst))
+
+	// Kasus uji untuk urutan kelahiran yang tidak dikenal
+	assert.Empty(t, balinese.BirthOrderNames(0))
+}
+
+func TestScore(t *testing.T) {
+	// Kasus uji untuk ejaan singkat yang sama dengan nama lengkap
+	assert.Equal(t, 1.0, balinese.Score("Ni Wyn Sari", "Ni Wayan Sari"))
+
+	// Kasus uji untuk urutan kelahiran yang setara
+	assert.Equal(t, 1.0, balinese.Score("Wayan Sari", "Putu Sari"))
+
+	// Kasus uji untuk urutan kelahiran berbeda yang menurunkan peringkat
+	assert.Less(t, balinese.Score("Wayan Sari", "Made Sari"), balinese.Score("Wayan Sari", "Putu Sari"))
+
+	// Kasus uji untuk nama inti yang berbeda
+	assert.Less(t, balinese.Score("Ni Wyn Sari", "Ni Wayan Suci"), balinese.Score("Ni Wyn Sari", "Made Sari"))
+
+	// Kasus uji untuk salah ketik kecil dan nama yang baru diketik sebagian
+	assert.Greater(t, balinese.Score("Sudarsana", "I Ketut Sudarsna"), 0.5)
+	assert.Equal(t, 0.9, balinese.Score("Sudar", "I Ketut Sudarsana"))
+
+	// Kasus uji untuk pencarian yang hanya berisi gelar dan urutan kelahiran
+	assert.Equal(t, 1.0, balinese.Score("Ni Wyn", "Ni Putu Ayu Lestari"))
+	assert.Equal(t, 0.0, balinese.Score("Ni Wyn", "Ni Kadek Lestari"))
+}
+
+func TestSimilarity(t *testing.T) {
+	// Kasus uji untuk nama yang sama dengan ejaan berbeda
+	assert.Equal(t, 1.0, balinese.Similarity("Ni Wyn Sari", "Ni Putu Sari"))
+
+	// Kasus uji untuk nama yang hanya memuat sebagian nama lainnya
+	assert.Less(t, balinese.Similarity("I Made Oka", "I Made Oka Wirawan"), balinese.Score("I Made Oka", "I Made Oka Wirawan"))
+
+	// Kasus uji untuk nama kosong
+	assert.Equal(t, 0.0, balinese.Similarity("", "I Made Oka"))
+}
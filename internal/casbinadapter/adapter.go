@@ -0,0 +1,193 @@
+package casbinadapter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/casbin/casbin/model"
+	"github.com/casbin/casbin/persist"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+// ruleColumns adalah kolom nilai aturan sesuai urutan pada policy.csv.
+var ruleColumns = []string{"v0", "v1", "v2", "v3", "v4", "v5"}
+
+// Adapter menyimpan policy Casbin pada tabel casbin_rules melalui GORM
+// sehingga perubahan aturan akses tidak memerlukan deploy ulang.
+type Adapter struct {
+	database  *gorm.DB
+	table     string
+	seedTable string
+}
+
+// NewAdapter membuat adapter untuk tabel casbin_rules. Tabel dibuat oleh
+// AutoMigrate pada bootstrap.
+func NewAdapter(db *gorm.DB) *Adapter {
+	return &Adapter{
+		database:  db,
+		table:     domain.CasbinRuleTable,
+		seedTable: domain.CasbinSeedTable,
+	}
+}
+
+// SeedPolicy menambahkan aturan pada model yang belum pernah disalin ke
+// tabel, misalnya aturan baru pada policy.csv di instalasi yang sudah
+// berjalan. Aturan yang pernah disalin lalu dihapus melalui editor policy
+// tidak ditambahkan lagi. SeedPolicy mengembalikan jumlah aturan yang
+// ditambahkan.
+func (a *Adapter) SeedPolicy(model model.Model) (added int, err error) {
+	rules, err := modelRules(model)
+	if err != nil {
+		return 0, err
+	}
+
+	err = a.database.Transaction(func(tx *gorm.DB) error {
+		for _, rule := range rules {
+			line := PolicyLine(rule)
+			var seeded int64
+			if err := tx.Table(a.seedTable).Where("line = ?", line).Count(&seeded).Error; err != nil {
+				return err
+			}
+			if seeded > 0 {
+				continue
+			}
+
+			var existing int64
+			if err := a.matchRule(tx, rule).Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing == 0 {
+				if err := tx.Table(a.table).Create(&rule).Error; err != nil {
+					return err
+				}
+				added++
+			}
+			if err := tx.Table(a.seedTable).Create(&domain.CasbinSeed{Line: line}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+func (a *Adapter) LoadPolicy(model model.Model) error {
+	var rules []domain.CasbinRule
+	if err := a.database.Table(a.table).Order("id ASC").Find(&rules).Error; err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		persist.LoadPolicyLine(PolicyLine(rule), model)
+	}
+	return nil
+}
+
+// SavePolicy mengganti seluruh isi tabel dengan policy pada model dalam satu
+// transaksi.
+func (a *Adapter) SavePolicy(model model.Model) error {
+	rules, err := modelRules(model)
+	if err != nil {
+		return err
+	}
+
+	return a.database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(a.table).Where("1 = 1").Delete(&domain.CasbinRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Table(a.table).Create(&rules).Error
+	})
+}
+
+func (a *Adapter) AddPolicy(sec string, ptype string, values []string) error {
+	rule, err := NewRule(ptype, values)
+	if err != nil {
+		return err
+	}
+	return a.database.Table(a.table).Create(&rule).Error
+}
+
+func (a *Adapter) RemovePolicy(sec string, ptype string, values []string) error {
+	rule, err := NewRule(ptype, values)
+	if err != nil {
+		return err
+	}
+	return a.matchRule(a.database, rule).Delete(&domain.CasbinRule{}).Error
+}
+
+func (a *Adapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	if fieldIndex < 0 || fieldIndex+len(fieldValues) > len(ruleColumns) {
+		return errors.New("policy filter is out of range")
+	}
+	query := a.database.Table(a.table).Where("p_type = ?", ptype)
+	for i, value := range fieldValues {
+		if value != "" {
+			query = query.Where(ruleColumns[fieldIndex+i]+" = ?", value)
+		}
+	}
+	return query.Delete(&domain.CasbinRule{}).Error
+}
+
+// matchRule menyaring baris tabel yang sama persis dengan rule.
+func (a *Adapter) matchRule(db *gorm.DB, rule domain.CasbinRule) *gorm.DB {
+	ruleValues := []string{rule.V0, rule.V1, rule.V2, rule.V3, rule.V4, rule.V5}
+	query := db.Table(a.table).Where("p_type = ?", rule.PType)
+	for i, column := range ruleColumns {
+		query = query.Where(column+" = ?", ruleValues[i])
+	}
+	return query
+}
+
+// modelRules menyusun seluruh aturan akses dan pewarisan peran pada model
+// menjadi baris tabel.
+func modelRules(model model.Model) ([]domain.CasbinRule, error) {
+	rules := []domain.CasbinRule{}
+	for _, sec := range []string{domain.CasbinPolicyType, domain.CasbinGroupingType} {
+		for ptype, assertion := range model[sec] {
+			for _, values := range assertion.Policy {
+				rule, err := NewRule(ptype, values)
+				if err != nil {
+					return nil, err
+				}
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// NewRule menyusun baris tabel dari jenis aturan dan nilainya.
+func NewRule(ptype string, values []string) (domain.CasbinRule, error) {
+	if len(values) > len(ruleColumns) {
+		return domain.CasbinRule{}, fmt.Errorf("policy has %d values, at most %d are supported", len(values), len(ruleColumns))
+	}
+	padded := make([]string, len(ruleColumns))
+	copy(padded, values)
+	return domain.CasbinRule{
+		PType: ptype,
+		V0:    padded[0],
+		V1:    padded[1],
+		V2:    padded[2],
+		V3:    padded[3],
+		V4:    padded[4],
+		V5:    padded[5],
+	}, nil
+}
+
+// PolicyLine menyusun baris tabel menjadi satu baris policy.csv, misalnya
+// "p, admin, /residents/*, GET". Nilai kosong di belakang diabaikan.
+func PolicyLine(rule domain.CasbinRule) string {
+	values := []string{rule.V0, rule.V1, rule.V2, rule.V3, rule.V4, rule.V5}
+	last := len(values)
+	for last > 0 && values[last-1] == "" {
+		last--
+	}
+	return strings.Join(append([]string{rule.PType}, values[:last]...), ", ")
+}
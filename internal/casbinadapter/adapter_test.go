@@ -0,0 +1,39 @@
+package casbinadapter_test
+
+import (
+	"testing"
+
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/casbinadapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRule(t *testing.T) {
+	// Kasus uji untuk aturan akses dengan tiga nilai
+	rule, err := casbinadapter.NewRule("p", []string{"admin", "/residents/*", "GET"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CasbinRule{PType: "p", V0: "admin", V1: "/residents/*", V2: "GET"}, rule)
+
+	// Kasus uji untuk pewarisan peran dengan dua nilai
+	rule, err = casbinadapter.NewRule("g", []string{"staff", "admin"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CasbinRule{PType: "g", V0: "staff", V1: "admin"}, rule)
+
+	// Kasus uji untuk nilai melebihi jumlah kolom
+	_, err = casbinadapter.NewRule("p", []string{"a", "b", "c", "d", "e", "f", "g"})
+	assert.Error(t, err)
+}
+
+func TestPolicyLine(t *testing.T) {
+	// Kasus uji untuk aturan akses
+	line := casbinadapter.PolicyLine(domain.CasbinRule{PType: "p", V0: "admin", V1: "/residents/*", V2: "GET"})
+	assert.Equal(t, "p, admin, /residents/*, GET", line)
+
+	// Kasus uji untuk pewarisan peran, nilai kosong di belakang diabaikan
+	line = casbinadapter.PolicyLine(domain.CasbinRule{PType: "g", V0: "staff", V1: "admin"})
+	assert.Equal(t, "g, staff, admin", line)
+
+	// Kasus uji untuk nilai kosong di tengah tetap dipertahankan
+	line = casbinadapter.PolicyLine(domain.CasbinRule{PType: "p", V0: "admin", V2: "GET"})
+	assert.Equal(t, "p, admin, , GET", line)
+}
@@ -0,0 +1,116 @@
+package casbinadapter
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+// Watcher memuat ulang policy ketika isi tabel casbin_rules berubah. Setiap
+// proses server memeriksa sidik tabel secara berkala sehingga perubahan dari
+// editor policy di satu proses ikut berlaku di seluruh proses lain tanpa
+// restart. Watcher memenuhi persist.Watcher milik Casbin.
+type Watcher struct {
+	database *gorm.DB
+	table    string
+	interval time.Duration
+	callback func(string)
+	last     string
+	mutex    sync.Mutex
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewWatcher mulai memeriksa tabel casbin_rules setiap interval.
+func NewWatcher(db *gorm.DB, interval time.Duration) (*Watcher, error) {
+	w := &Watcher{
+		database: db,
+		table:    domain.CasbinRuleTable,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+	last, err := w.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	w.last = last
+
+	go w.run()
+	return w, nil
+}
+
+func (w *Watcher) SetUpdateCallback(callback func(string)) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.callback = callback
+	return nil
+}
+
+// Update memeriksa tabel tanpa menunggu interval berikutnya. Proses lain
+// menemukan perubahan yang sama pada pemeriksaan berkalanya. Pemeriksaan
+// berjalan di goroutine terpisah karena Update dipanggil SyncedEnforcer
+// selagi memegang kuncinya, sedangkan callback memuat ulang policy melalui
+// kunci yang sama.
+func (w *Watcher) Update() error {
+	go func() {
+		if err := w.check(); err != nil {
+			log.Printf("failed to check casbin policy changes: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+func (w *Watcher) Close() {
+	w.once.Do(func() { close(w.done) })
+}
+
+func (w *Watcher) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			if err := w.check(); err != nil {
+				log.Printf("failed to check casbin policy changes: %v\n", err)
+			}
+		}
+	}
+}
+
+func (w *Watcher) check() error {
+	current, err := w.fingerprint()
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	changed := current != w.last
+	w.last = current
+	callback := w.callback
+	w.mutex.Unlock()
+
+	if changed && callback != nil {
+		callback(current)
+	}
+	return nil
+}
+
+// fingerprint meringkas isi tabel menjadi jumlah baris, id terbesar dan
+// checksum seluruh nilai sehingga penambahan, penghapusan maupun perubahan
+// langsung di database terdeteksi tanpa memuat seluruh aturan.
+func (w *Watcher) fingerprint() (string, error) {
+	var count, maxID, checksum int64
+	row := w.database.Table(w.table).
+		Select("COUNT(*), COALESCE(MAX(id), 0), COALESCE(SUM(CRC32(CONCAT_WS(',', p_type, v0, v1, v2, v3, v4, v5))), 0)").
+		Row()
+	if err := row.Scan(&count, &maxID, &checksum); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d:%d:%d", count, maxID, checksum), nil
+}
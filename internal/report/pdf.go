@@ -0,0 +1,181 @@
+package report
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/koropati/population-recap/domain"
+)
+
+const (
+	pdfMargin     = 15.0
+	pdfLineHeight = 6.0
+	pdfFont       = "Helvetica"
+
+	// pdfLandscapeColumns adalah jumlah kolom minimal agar laporan dicetak
+	// mendatar.
+	pdfLandscapeColumns = 8
+	// pdfSignatureHeight adalah tinggi yang disediakan untuk blok tanda tangan.
+	pdfSignatureHeight = 45.0
+)
+
+func writePDF(w io.Writer, recap domain.RecapReport, signature domain.ReportSignature) error {
+	orientation := "P"
+	for _, table := range recap.Tables {
+		if len(table.Header) >= pdfLandscapeColumns {
+			orientation = "L"
+		}
+	}
+
+	pdf := fpdf.New(orientation, "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	writePDFHeader(pdf, tr, recap)
+	for _, table := range recap.Tables {
+		writePDFTable(pdf, tr, table)
+	}
+	writePDFSignature(pdf, tr, signature)
+
+	return pdf.Output(w)
+}
+
+// writePDFHeader menulis kop laporan: judul, nama kecamatan, wilayah dan
+// periode laporan, diakhiri garis pemisah.
+func writePDFHeader(pdf *fpdf.Fpdf, tr func(string) string, recap domain.RecapReport) {
+	width := contentWidth(pdf)
+
+	pdf.SetFont(pdfFont, "B", 13)
+	pdf.MultiCell(width, 7, tr(strings.ToUpper(recap.Title)), "", "C", false)
+	pdf.CellFormat(width, 7, tr("KECAMATAN "+strings.ToUpper(recap.Kecamatan)), "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfFont, "", 10)
+	if recap.RegionName != "" {
+		pdf.CellFormat(width, pdfLineHeight, tr("Wilayah: "+recap.RegionName), "", 1, "C", false, 0, "")
+	}
+	pdf.CellFormat(width, pdfLineHeight, tr(recap.Period), "", 1, "C", false, 0, "")
+
+	y := pdf.GetY() + 2
+	pdf.SetLineWidth(0.6)
+	pdf.Line(pdfMargin, y, pdfMargin+width, y)
+	pdf.SetLineWidth(0.2)
+	pdf.SetY(y + 4)
+}
+
+// writePDFTable menulis tabel dengan lebar kolom menyesuaikan isi. Header
+// tabel diulang setiap kali tabel berlanjut ke halaman berikutnya.
+func writePDFTable(pdf *fpdf.Fpdf, tr func(string) string, table domain.ReportTable) {
+	if table.Title != "" {
+		pdf.SetFont(pdfFont, "B", 10)
+		pdf.CellFormat(contentWidth(pdf), pdfLineHeight, tr(table.Title), "", 1, "L", false, 0, "")
+	}
+	widths := columnWidths(pdf, tr, table)
+
+	header := func() {
+		pdf.SetFont(pdfFont, "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for i, value := range table.Header {
+			pdf.CellFormat(widths[i], pdfLineHeight+1, tr(value), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	row := func(values []string, style string) {
+		_, pageHeight := pdf.GetPageSize()
+		if pdf.GetY()+pdfLineHeight > pageHeight-pdfMargin {
+			pdf.AddPage()
+			header()
+		}
+		pdf.SetFont(pdfFont, style, 9)
+		for i := range widths {
+			value := ""
+			if i < len(values) {
+				value = values[i]
+			}
+			align := "L"
+			if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+				align = "R"
+			}
+			pdf.CellFormat(widths[i], pdfLineHeight, tr(value), "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	header()
+	for _, values := range table.Rows {
+		row(values, "")
+	}
+	if table.Footer != nil {
+		row(table.Footer, "B")
+	}
+	pdf.Ln(pdfLineHeight)
+}
+
+// writePDFSignature menulis blok tanda tangan di sisi kanan halaman.
+func writePDFSignature(pdf *fpdf.Fpdf, tr func(string) string, signature domain.ReportSignature) {
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+pdfSignatureHeight > pageHeight-pdfMargin {
+		pdf.AddPage()
+	}
+
+	width := 80.0
+	x := pdfMargin + contentWidth(pdf) - width
+	place := FormatDate(signature.Date)
+	if signature.City != "" {
+		place = signature.City + ", " + place
+	}
+	line := func(text string, style string, height float64) {
+		pdf.SetX(x)
+		pdf.SetFont(pdfFont, style, 10)
+		pdf.CellFormat(width, height, tr(text), "", 1, "C", false, 0, "")
+	}
+
+	line(place, "", pdfLineHeight)
+	line(signature.Position, "", pdfLineHeight)
+	pdf.Ln(20)
+	line(signature.Name, "BU", pdfLineHeight)
+	if signature.NIP != "" {
+		line("NIP. "+signature.NIP, "", pdfLineHeight)
+	}
+}
+
+// columnWidths menghitung lebar kolom dari teks terpanjang lalu
+// menyesuaikannya dengan lebar halaman.
+func columnWidths(pdf *fpdf.Fpdf, tr func(string) string, table domain.ReportTable) []float64 {
+	widths := make([]float64, len(table.Header))
+	measure := func(values []string, style string) {
+		pdf.SetFont(pdfFont, style, 9)
+		for i := range widths {
+			if i < len(values) {
+				if width := pdf.GetStringWidth(tr(values[i])) + 4; width > widths[i] {
+					widths[i] = width
+				}
+			}
+		}
+	}
+	measure(table.Header, "B")
+	for _, values := range table.Rows {
+		measure(values, "")
+	}
+	measure(table.Footer, "B")
+
+	total := 0.0
+	for _, width := range widths {
+		total += width
+	}
+	if total == 0 {
+		return widths
+	}
+	scale := contentWidth(pdf) / total
+	for i := range widths {
+		widths[i] *= scale
+	}
+	return widths
+}
+
+func contentWidth(pdf *fpdf.Fpdf) float64 {
+	pageWidth, _ := pdf.GetPageSize()
+	return pageWidth - 2*pdfMargin
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/spreadsheet"
+)
+
+const (
+	FormatPDF = "pdf"
+
+	ContentTypePDF = "application/pdf"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported report format, use xlsx, csv or pdf")
+
+var monthNames = []string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// IsSupported memeriksa apakah laporan dapat ditulis dalam format tersebut.
+func IsSupported(format string) bool {
+	return format == FormatPDF || spreadsheet.IsSupported(format)
+}
+
+// ContentType mengembalikan MIME type untuk format laporan.
+func ContentType(format string) string {
+	if format == FormatPDF {
+		return ContentTypePDF
+	}
+	return spreadsheet.ContentType(format)
+}
+
+// Write menulis laporan rekap. PDF memakai tata letak laporan resmi dengan
+// kop kecamatan dan blok tanda tangan, sedangkan CSV dan XLSX hanya memuat
+// judul dan tabel agar mudah diolah kembali.
+func Write(format string, w io.Writer, recap domain.RecapReport, signature domain.ReportSignature) error {
+	if format == FormatPDF {
+		return writePDF(w, recap, signature)
+	}
+	if !spreadsheet.IsSupported(format) {
+		return ErrUnsupportedFormat
+	}
+
+	rows := [][]string{
+		{"Kecamatan " + recap.Kecamatan},
+		{recap.RegionName},
+		{recap.Period},
+	}
+	for _, table := range recap.Tables {
+		rows = append(rows, []string{})
+		if table.Title != "" {
+			rows = append(rows, []string{table.Title})
+		}
+		rows = append(rows, table.Header)
+		rows = append(rows, table.Rows...)
+		if table.Footer != nil {
+			rows = append(rows, table.Footer)
+		}
+	}
+	return spreadsheet.Write(format, w, []string{recap.Title}, rows)
+}
+
+// FormatDate menulis tanggal dalam bahasa Indonesia, misalnya 17 Agustus 2024.
+func FormatDate(t time.Time) string {
+	return strconv.Itoa(t.Day()) + " " + FormatMonth(t)
+}
+
+// FormatMonth menulis bulan dan tahun dalam bahasa Indonesia.
+func FormatMonth(t time.Time) string {
+	return monthNames[t.Month()-1] + " " + strconv.Itoa(t.Year())
+}
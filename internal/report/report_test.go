@@ -0,0 +1,59 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/report"
+	"github.com/koropati/population-recap/internal/spreadsheet"
+	"github.com/stretchr/testify/assert"
+)
+
+var recap = domain.RecapReport{
+	Title:      "Rekapitulasi Penduduk Menurut Kelompok Umur dan Jenis Kelamin",
+	Kecamatan:  "Bangli",
+	RegionName: "Kelurahan Cempaga",
+	Period:     "Keadaan per 17 Agustus 2024",
+	Tables: []domain.ReportTable{{
+		Header: []string{"Kelompok Umur", "Laki-laki", "Perempuan", "Jumlah"},
+		Rows:   [][]string{{"0-4", "10", "12", "22"}, {"5-9", "8", "9", "17"}},
+		Footer: []string{"Jumlah", "18", "21", "39"},
+	}},
+}
+
+var signature = domain.ReportSignature{
+	City:     "Bangli",
+	Date:     time.Date(2024, time.August, 17, 0, 0, 0, 0, time.Local),
+	Position: "Camat Bangli",
+	Name:     "I Wayan Sudarma",
+	NIP:      "197005152000031002",
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, time.August, 17, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, "17 Agustus 2024", report.FormatDate(date))
+	assert.Equal(t, "Agustus 2024", report.FormatMonth(date))
+}
+
+func TestWriteSpreadsheet(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, report.Write(spreadsheet.FormatCSV, &buffer, recap, signature))
+
+	lines, err := spreadsheet.Read(spreadsheet.FormatCSV, &buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{recap.Title}, lines[0])
+	assert.Equal(t, []string{"Kecamatan Bangli"}, lines[1])
+	assert.Contains(t, lines, recap.Tables[0].Header)
+	assert.Equal(t, recap.Tables[0].Footer, lines[len(lines)-1])
+}
+
+func TestWritePDF(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, report.Write(report.FormatPDF, &buffer, recap, signature))
+	assert.True(t, bytes.HasPrefix(buffer.Bytes(), []byte("%PDF-")))
+
+	// Kasus uji format yang tidak didukung
+	assert.ErrorIs(t, report.Write("doc", &buffer, recap, signature), report.ErrUnsupportedFormat)
+}
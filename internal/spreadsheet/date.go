@@ -0,0 +1,40 @@
+package spreadsheet
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// dateLayouts adalah format tanggal yang umum dipakai pada berkas desa.
+var dateLayouts = []string{
+	"2006-01-02",
+	"02-01-2006",
+	"02/01/2006",
+	"2/1/2006",
+	"02.01.2006",
+}
+
+// ParseDate membaca tanggal dalam format teks maupun nomor seri Excel.
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, errors.New("date is empty")
+	}
+	for _, layout := range dateLayouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		date, err := excelize.ExcelDateToTime(serial, false)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local), nil
+	}
+	return time.Time{}, errors.New("unrecognized date " + value + ", use YYYY-MM-DD or DD-MM-YYYY")
+}
@@ -0,0 +1,113 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	ContentTypeCSV  = "text/csv"
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// DefaultSheet adalah nama sheet untuk berkas XLSX yang ditulis.
+	DefaultSheet = "Sheet1"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported file format, use csv or xlsx")
+
+// FormatFromFilename menentukan format berkas dari ekstensinya.
+func FormatFromFilename(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// IsSupported memeriksa apakah format berkas dapat dibaca dan ditulis.
+func IsSupported(format string) bool {
+	return format == FormatCSV || format == FormatXLSX
+}
+
+// ContentType mengembalikan MIME type untuk format berkas.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return ContentTypeXLSX
+	}
+	return ContentTypeCSV
+}
+
+// Read membaca seluruh baris berkas CSV atau sheet pertama berkas XLSX.
+// Sel tanggal pada XLSX dikembalikan sebagai nomor seri Excel apa adanya,
+// gunakan ParseDate untuk mengubahnya.
+func Read(format string, r io.Reader) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case FormatXLSX:
+		file, err := excelize.OpenReader(r, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		sheets := file.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("workbook has no sheet")
+		}
+		return file.GetRows(sheets[0])
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// Write menulis header dan baris data sebagai CSV atau XLSX.
+func Write(format string, w io.Writer, header []string, rows [][]string) error {
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	case FormatXLSX:
+		file := excelize.NewFile()
+		defer file.Close()
+		if err := writeSheetRow(file, 1, header); err != nil {
+			return err
+		}
+		for i, row := range rows {
+			if err := writeSheetRow(file, i+2, row); err != nil {
+				return err
+			}
+		}
+		return file.Write(w)
+	}
+	return ErrUnsupportedFormat
+}
+
+func writeSheetRow(file *excelize.File, number int, values []string) error {
+	cell, err := excelize.CoordinatesToCellName(1, number)
+	if err != nil {
+		return err
+	}
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		row[i] = value
+	}
+	return file.SetSheetRow(DefaultSheet, cell, &row)
+}
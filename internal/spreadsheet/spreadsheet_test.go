@@ -0,0 +1,54 @@
+package spreadsheet_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/koropati/population-recap/internal/spreadsheet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatFromFilename(t *testing.T) {
+	format, err := spreadsheet.FormatFromFilename("penduduk.CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, spreadsheet.FormatCSV, format)
+
+	format, err = spreadsheet.FormatFromFilename("data/penduduk.xlsx")
+	assert.NoError(t, err)
+	assert.Equal(t, spreadsheet.FormatXLSX, format)
+
+	// Kasus uji format xls lama tidak didukung
+	_, err = spreadsheet.FormatFromFilename("penduduk.xls")
+	assert.ErrorIs(t, err, spreadsheet.ErrUnsupportedFormat)
+}
+
+func TestWriteRead(t *testing.T) {
+	header := []string{"nik", "name"}
+	rows := [][]string{{"5106021505900002", "I Wayan Sudarma"}, {"5106024505900001", "Ni Made Sari"}}
+
+	for _, format := range []string{spreadsheet.FormatCSV, spreadsheet.FormatXLSX} {
+		var buffer bytes.Buffer
+		assert.NoError(t, spreadsheet.Write(format, &buffer, header, rows))
+
+		lines, err := spreadsheet.Read(format, &buffer)
+		assert.NoError(t, err)
+		assert.Equal(t, append([][]string{header}, rows...), lines, format)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	expected := time.Date(1990, time.May, 15, 0, 0, 0, 0, time.Local)
+
+	for _, value := range []string{"1990-05-15", "15-05-1990", "15/05/1990", "15/5/1990", "15.05.1990", "33008"} {
+		date, err := spreadsheet.ParseDate(value)
+		assert.NoError(t, err, value)
+		assert.True(t, expected.Equal(date), value)
+	}
+
+	// Kasus uji tanggal kosong dan tidak dikenali
+	_, err := spreadsheet.ParseDate("")
+	assert.Error(t, err)
+	_, err = spreadsheet.ParseDate("15 Mei 1990")
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package tokenutil
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -21,13 +22,46 @@ const (
 	AnonymousRole        = "anonymous"
 )
 
-func CreateAccessToken(user *domain.User, secret string, expiry int, accessTokenUsecase domain.AccessTokenUsecase) (accessToken string, err error) {
+// ErrRefreshTokenReused dikembalikan bila refresh token yang sudah dirotasi
+// dipakai lagi. Hal ini menandakan token bocor sehingga seluruh token hasil
+// login yang sama dicabut.
+var ErrRefreshTokenReused = errors.New("refresh token has already been used, please login again")
+
+// CreateTokenPair membuat access dan refresh token untuk login baru. Kedua
+// token mendapat SessionID baru yang mewakili satu perangkat.
+func CreateTokenPair(user *domain.User, accessSecret string, accessExpiry int, refreshSecret string, refreshExpiry int, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) (accessToken string, refreshToken string, err error) {
+	sessionID, err := uuid.NewUUID()
+	if err != nil {
+		return "", "", err
+	}
+
+	accessToken, err = CreateAccessToken(user, accessSecret, accessExpiry, sessionID, accessTokenUsecase)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, err = CreateRefreshToken(user, refreshSecret, refreshExpiry, accessToken, sessionID, refreshTokenUsecase)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+func CreateAccessToken(user *domain.User, secret string, expiry int, sessionID uuid.UUID, accessTokenUsecase domain.AccessTokenUsecase) (accessToken string, err error) {
+	uuidData, err := uuid.NewUUID()
+	if err != nil {
+		return "", err
+	}
+
+	// Id (jti) membuat setiap token unik walaupun dibuat pada detik yang sama.
 	exp := time.Now().Add(time.Hour * time.Duration(expiry)).Unix()
 	claims := &domain.JwtCustomClaims{
-		Name: user.Name,
-		ID:   user.ID.String(),
-		Role: user.Role,
+		Name:      user.Name,
+		ID:        user.ID.String(),
+		Role:      user.Role,
+		SessionID: sessionID.String(),
 		StandardClaims: jwt.StandardClaims{
+			Id:        uuidData.String(),
 			ExpiresAt: exp,
 		},
 	}
@@ -36,16 +70,12 @@ func CreateAccessToken(user *domain.User, secret string, expiry int, accessToken
 	if err != nil {
 		return "", err
 	}
-
-	uuidData, err := uuid.NewUUID()
-	if err != nil {
-		return "", err
-	}
 	// Set token in Redis with expiry
 	err = accessTokenUsecase.Create(context.Background(), domain.AccessToken{
 		ID:        uuidData,
 		Token:     t,
 		UserID:    user.ID,
+		SessionID: sessionID,
 		Revoked:   false,
 		CreatedAt: time.Now().Unix(),
 		ExpiresAt: exp,
@@ -57,13 +87,22 @@ func CreateAccessToken(user *domain.User, secret string, expiry int, accessToken
 	return t, err
 }
 
-func CreateRefreshToken(user *domain.User, secret string, expiry int, accessToken string, refreshTokenUsecase domain.RefreshTokenUsecase) (refreshToken string, err error) {
+// CreateRefreshToken membuat refresh token yang dipasangkan dengan
+// accessToken pada session yang sama.
+func CreateRefreshToken(user *domain.User, secret string, expiry int, accessToken string, sessionID uuid.UUID, refreshTokenUsecase domain.RefreshTokenUsecase) (refreshToken string, err error) {
+	uuidData, err := uuid.NewUUID()
+	if err != nil {
+		return "", err
+	}
+
 	exp := time.Now().Add(time.Hour * time.Duration(expiry)).Unix()
 	claimsRefresh := &domain.JwtCustomRefreshClaims{
-		Name: user.Name,
-		ID:   user.ID.String(),
-		Role: user.Role,
+		Name:      user.Name,
+		ID:        user.ID.String(),
+		Role:      user.Role,
+		SessionID: sessionID.String(),
 		StandardClaims: jwt.StandardClaims{
+			Id:        uuidData.String(),
 			ExpiresAt: exp,
 		},
 	}
@@ -73,15 +112,117 @@ func CreateRefreshToken(user *domain.User, secret string, expiry int, accessToke
 		return "", err
 	}
 
+	// Set token in Redis with expiry
+	err = refreshTokenUsecase.Create(context.Background(), domain.RefreshToken{
+		ID:          uuidData,
+		Token:       rt,
+		PairTokenID: tokenID(accessToken),
+		UserID:      user.ID,
+		SessionID:   sessionID,
+		Revoked:     false,
+		CreatedAt:   time.Now().Unix(),
+		ExpiresAt:   exp,
+	})
+	if err != nil {
+		return "", err
+	}
+	return rt, err
+}
+
+// tokenID membaca ID (jti) token tanpa memeriksa tanda tangannya, dipakai
+// untuk mencocokkan token yang sudah tersimpan.
+func tokenID(token string) string {
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err != nil {
+		return ""
+	}
+	id, _ := claims["jti"].(string)
+	return id
+}
+
+// RotateRefreshToken menukar refresh token yang masih berlaku dengan pasangan
+// access dan refresh token baru pada session yang sama. Refresh token lama
+// ditandai sudah dirotasi dan access token lama (bila diketahui) dicabut.
+// Pemakaian ulang refresh token yang sudah dirotasi mencabut seluruh token
+// pada session tersebut.
+func RotateRefreshToken(refreshToken string, accessToken string, accessSecret string, accessExpiry int, refreshSecret string, refreshExpiry int, userUsecase domain.UserUsecase, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) (newAccessToken string, newRefreshToken string, err error) {
+	ctx := context.Background()
+
+	if !refreshTokenUsecase.IsValid(ctx, refreshToken) {
+		stored, err := refreshTokenUsecase.GetByToken(ctx, refreshToken)
+		if err == nil && stored.RotatedAt > 0 && stored.SessionID != uuid.Nil {
+			RevokeSession(stored.SessionID, accessTokenUsecase, refreshTokenUsecase)
+			return "", "", ErrRefreshTokenReused
+		}
+		return "", "", errors.New(ErrorInvalidToken)
+	}
+
+	claims, err := ParseJWTToken(refreshToken, refreshSecret)
+	if err != nil {
+		return "", "", err
+	}
+	userID, err := uuid.Parse(fmt.Sprint(claims["id"]))
+	if err != nil {
+		return "", "", errors.New(ErrorInvalidToken)
+	}
+	user, err := userUsecase.GetById(ctx, userID)
+	if err != nil {
+		return "", "", errors.New(ErrorInvalidToken)
+	}
+	if !user.IsActive {
+		return "", "", errors.New("user is not active")
+	}
+
+	stored, err := refreshTokenUsecase.GetByToken(ctx, refreshToken)
+	if err != nil {
+		return "", "", err
+	}
+	// Rotate gagal bila permintaan lain sudah menukar token yang sama lebih
+	// dulu, hal ini juga diperlakukan sebagai pemakaian ulang.
+	if err := refreshTokenUsecase.Rotate(ctx, refreshToken); err != nil {
+		if stored.SessionID != uuid.Nil {
+			RevokeSession(stored.SessionID, accessTokenUsecase, refreshTokenUsecase)
+		}
+		return "", "", ErrRefreshTokenReused
+	}
+	if pairID, err := uuid.Parse(stored.PairTokenID); err == nil {
+		accessTokenUsecase.RevokeByID(ctx, pairID)
+	}
+	if accessToken != "" && tokenID(accessToken) != stored.PairTokenID {
+		accessTokenUsecase.Revoke(ctx, accessToken)
+	}
+
+	// Token yang dibuat sebelum session tersedia belum memiliki SessionID.
+	sessionID := stored.SessionID
+	if sessionID == uuid.Nil {
+		if sessionID, err = uuid.NewUUID(); err != nil {
+			return "", "", err
+		}
+	}
+	newAccessToken, err = CreateAccessToken(&user, accessSecret, accessExpiry, sessionID, accessTokenUsecase)
+	if err != nil {
+		return "", "", err
+	}
+	newRefreshToken, err = CreateRefreshToken(&user, refreshSecret, refreshExpiry, newAccessToken, sessionID, refreshTokenUsecase)
+	if err != nil {
+		return "", "", err
+	}
+	return newAccessToken, newRefreshToken, nil
+}
+
+func CreateForgotToken(user *domain.User, expiry int, forgotPasswordTokenUsecase domain.ForgotPasswordTokenUsecase) (forgotToken string, err error) {
+	exp := time.Now().Add(time.Hour * time.Duration(expiry)).Unix()
 	uuidData, err := uuid.NewUUID()
 	if err != nil {
 		return "", err
 	}
 
-	// Set token in Redis with expiry
-	err = refreshTokenUsecase.Create(context.Background(), domain.RefreshToken{
+	randStr := randomstr.New(true, true, true, false)
+	tokenData := randStr.GenerateRandomString(24)
+
+	err = forgotPasswordTokenUsecase.Create(context.Background(), domain.ForgotPasswordToken{
 		ID:        uuidData,
-		Token:     rt,
+		Token:     tokenData,
 		UserID:    user.ID,
 		Revoked:   false,
 		CreatedAt: time.Now().Unix(),
@@ -90,10 +231,11 @@ func CreateRefreshToken(user *domain.User, secret string, expiry int, accessToke
 	if err != nil {
 		return "", err
 	}
-	return rt, err
+
+	return tokenData, nil
 }
 
-func CreateForgotToken(user *domain.User, expiry int, forgotPasswordTokenUsecase domain.ForgotPasswordTokenUsecase) (forgotToken string, err error) {
+func CreateVerificationEmailToken(user *domain.User, expiry int, verificationEmailTokenUsecase domain.VerificationEmailTokenUsecase) (verificationToken string, err error) {
 	exp := time.Now().Add(time.Hour * time.Duration(expiry)).Unix()
 	uuidData, err := uuid.NewUUID()
 	if err != nil {
@@ -103,7 +245,7 @@ func CreateForgotToken(user *domain.User, expiry int, forgotPasswordTokenUsecase
 	randStr := randomstr.New(true, true, true, false)
 	tokenData := randStr.GenerateRandomString(24)
 
-	err = forgotPasswordTokenUsecase.Create(context.Background(), domain.ForgotPasswordToken{
+	err = verificationEmailTokenUsecase.Create(context.Background(), domain.VerificationEmailToken{
 		ID:        uuidData,
 		Token:     tokenData,
 		UserID:    user.ID,
@@ -126,6 +268,8 @@ func IsAuthorized(requestToken string, secret string) (bool, error) {
 	return true, nil
 }
 
+// RevokeToken mencabut access token beserta seluruh token pada session yang
+// sama sehingga hanya perangkat pemilik token yang keluar.
 func RevokeToken(accessToken string, secret string, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) error {
 	// Ekstrak ID pengguna dari token
 	_, _, err := ExtractIDFromToken(accessToken, secret, AccessToken, accessTokenUsecase, refreshTokenUsecase)
@@ -133,12 +277,21 @@ func RevokeToken(accessToken string, secret string, accessTokenUsecase domain.Ac
 		return err
 	}
 
+	stored, err := accessTokenUsecase.GetByToken(context.Background(), accessToken)
+	if err != nil {
+		return err
+	}
+	if stored.SessionID != uuid.Nil {
+		return RevokeSession(stored.SessionID, accessTokenUsecase, refreshTokenUsecase)
+	}
+
+	// Token lama tanpa session hanya dapat dicabut lewat pasangannya.
 	err = accessTokenUsecase.Revoke(context.Background(), accessToken)
 	if err != nil {
 		return err
 	}
 
-	err = refreshTokenUsecase.RevokeByPairToken(context.Background(), accessToken)
+	err = refreshTokenUsecase.RevokeByPairTokenID(context.Background(), tokenID(accessToken))
 	if err != nil {
 		return err
 	}
@@ -146,29 +299,39 @@ func RevokeToken(accessToken string, secret string, accessTokenUsecase domain.Ac
 	return nil
 }
 
-func RevokeAll(accessToken string, secret string, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) error {
-	userId, _, err := ExtractIDFromToken(accessToken, secret, AccessToken, accessTokenUsecase, refreshTokenUsecase)
+// RevokeSession mencabut seluruh access dan refresh token pada satu session.
+func RevokeSession(sessionID uuid.UUID, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) error {
+	err := accessTokenUsecase.RevokeBySessionID(context.Background(), sessionID)
 	if err != nil {
 		return err
 	}
 
-	userID, err := uuid.Parse(userId)
+	return refreshTokenUsecase.RevokeBySessionID(context.Background(), sessionID)
+}
+
+func RevokeAll(accessToken string, secret string, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) error {
+	userId, _, err := ExtractIDFromToken(accessToken, secret, AccessToken, accessTokenUsecase, refreshTokenUsecase)
 	if err != nil {
 		return err
 	}
 
-	err = accessTokenUsecase.RevokeByUserID(context.Background(), userID)
+	userID, err := uuid.Parse(userId)
 	if err != nil {
 		return err
 	}
 
-	err = refreshTokenUsecase.RevokeByUserID(context.Background(), userID)
+	return RevokeByUserID(userID, accessTokenUsecase, refreshTokenUsecase)
+}
+
+// RevokeByUserID mencabut seluruh token milik pengguna di semua perangkat,
+// dipakai saat akun dinonaktifkan atau perannya diubah.
+func RevokeByUserID(userID uuid.UUID, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) error {
+	err := accessTokenUsecase.RevokeByUserID(context.Background(), userID)
 	if err != nil {
 		return err
 	}
 
-	return nil
-
+	return refreshTokenUsecase.RevokeByUserID(context.Background(), userID)
 }
 
 func ExtractIDFromToken(requestToken string, secret string, tokenType string, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) (userId string, userRole string, err error) {
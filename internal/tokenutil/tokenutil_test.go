@@ -0,0 +1,246 @@
+package tokenutil_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/tokenutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	accessSecret  = "access-secret"
+	refreshSecret = "refresh-secret"
+)
+
+// memoryTokens menyimpan access dan refresh token di memori sebagai pengganti
+// repository pada pengujian.
+type memoryTokens struct {
+	access  map[string]*domain.AccessToken
+	refresh map[string]*domain.RefreshToken
+}
+
+type accessTokens struct{ *memoryTokens }
+
+type refreshTokens struct{ *memoryTokens }
+
+type users struct{ user domain.User }
+
+func newMemoryTokens() *memoryTokens {
+	return &memoryTokens{access: map[string]*domain.AccessToken{}, refresh: map[string]*domain.RefreshToken{}}
+}
+
+func (m accessTokens) Create(c context.Context, token domain.AccessToken) error {
+	m.access[token.Token] = &token
+	return nil
+}
+
+func (m accessTokens) GetByToken(c context.Context, token string) (domain.AccessToken, error) {
+	if stored, ok := m.access[token]; ok {
+		return *stored, nil
+	}
+	return domain.AccessToken{}, errors.New("not found")
+}
+
+func (m accessTokens) Revoke(c context.Context, token string) error {
+	if stored, ok := m.access[token]; ok {
+		stored.Revoked = true
+	}
+	return nil
+}
+
+func (m accessTokens) RevokeByID(c context.Context, id uuid.UUID) error {
+	for _, stored := range m.access {
+		if stored.ID == id {
+			stored.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (m accessTokens) RevokeBySessionID(c context.Context, sessionID uuid.UUID) error {
+	for _, stored := range m.access {
+		if stored.SessionID == sessionID {
+			stored.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (m accessTokens) RevokeByUserID(c context.Context, userID uuid.UUID) error { return nil }
+
+func (m accessTokens) IsValid(c context.Context, token string) bool {
+	stored, ok := m.access[token]
+	return ok && !stored.Revoked
+}
+
+func (m accessTokens) Delete(c context.Context, token string) error { return nil }
+
+func (m accessTokens) DeleteExpiredToken(c context.Context, millisDateTime int64) error { return nil }
+
+func (m refreshTokens) Create(c context.Context, token domain.RefreshToken) error {
+	m.refresh[token.Token] = &token
+	return nil
+}
+
+func (m refreshTokens) GetByToken(c context.Context, token string) (domain.RefreshToken, error) {
+	if stored, ok := m.refresh[token]; ok {
+		return *stored, nil
+	}
+	return domain.RefreshToken{}, errors.New("not found")
+}
+
+func (m refreshTokens) Revoke(c context.Context, token string) error { return nil }
+
+func (m refreshTokens) Rotate(c context.Context, token string) error {
+	stored, ok := m.refresh[token]
+	if !ok || stored.Revoked {
+		return errors.New("no token was updated")
+	}
+	stored.Revoked = true
+	stored.RotatedAt = time.Now().Unix()
+	return nil
+}
+
+func (m refreshTokens) RevokeBySessionID(c context.Context, sessionID uuid.UUID) error {
+	for _, stored := range m.refresh {
+		if stored.SessionID == sessionID {
+			stored.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (m refreshTokens) RevokeByPairTokenID(c context.Context, pairTokenID string) error {
+	for _, stored := range m.refresh {
+		if stored.PairTokenID == pairTokenID {
+			stored.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (m refreshTokens) RevokeByUserID(c context.Context, userID uuid.UUID) error { return nil }
+
+func (m refreshTokens) IsValid(c context.Context, token string) bool {
+	stored, ok := m.refresh[token]
+	return ok && !stored.Revoked && stored.ExpiresAt > time.Now().Unix()
+}
+
+func (m refreshTokens) Delete(c context.Context, token string) error { return nil }
+
+func (m refreshTokens) DeleteExpiredToken(c context.Context, millisDateTime int64) error { return nil }
+
+func (u users) Create(c context.Context, user domain.User) error { return nil }
+
+func (u users) Retrieve(c context.Context, filter domain.Filter) ([]domain.User, domain.MetaResponse, error) {
+	return []domain.User{u.user}, domain.MetaResponse{}, nil
+}
+
+func (u users) GetByEmail(c context.Context, email string) (domain.User, error) { return u.user, nil }
+
+func (u users) GetById(c context.Context, id uuid.UUID) (domain.User, error) { return u.user, nil }
+
+func (u users) Update(c context.Context, id uuid.UUID, data domain.User) (domain.User, error) {
+	return u.user, nil
+}
+
+func (u users) UpdatePassword(c context.Context, id uuid.UUID, newPasswordHash string) error {
+	return nil
+}
+
+func (u users) VerifyEmail(c context.Context, id uuid.UUID) error { return nil }
+
+func (u users) UpdateStatus(c context.Context, id uuid.UUID, isActive bool) error { return nil }
+
+func (u users) UpdateRole(c context.Context, id uuid.UUID, role string) error { return nil }
+
+func (u users) GetRegions(c context.Context, id uuid.UUID) ([]domain.Region, error) {
+	return nil, nil
+}
+
+func (u users) GetAssignments(c context.Context, ids []uuid.UUID) ([]domain.UserRegion, error) {
+	return nil, nil
+}
+
+func (u users) SetRegions(c context.Context, id uuid.UUID, regionIDs []uuid.UUID) error {
+	return nil
+}
+
+func (u users) Delete(c context.Context, id uuid.UUID) error { return nil }
+
+func (u users) Restore(c context.Context, id uuid.UUID) (domain.User, error) { return u.user, nil }
+
+func TestRotateRefreshToken(t *testing.T) {
+	store := newMemoryTokens()
+	at, rt := accessTokens{store}, refreshTokens{store}
+	user := domain.User{ID: uuid.New(), Name: "Operator", Role: "admin", IsActive: true}
+	uu := users{user}
+
+	accessToken, refreshToken, err := tokenutil.CreateTokenPair(&user, accessSecret, 1, refreshSecret, 24, at, rt)
+	assert.NoError(t, err)
+
+	// Kasus uji rotasi mencabut pasangan lama dan menerbitkan pasangan baru
+	newAccessToken, newRefreshToken, err := tokenutil.RotateRefreshToken(refreshToken, accessToken, accessSecret, 1, refreshSecret, 24, uu, at, rt)
+	assert.NoError(t, err)
+	assert.NotEqual(t, refreshToken, newRefreshToken)
+	assert.False(t, at.IsValid(context.Background(), accessToken))
+	assert.True(t, at.IsValid(context.Background(), newAccessToken))
+	assert.False(t, rt.IsValid(context.Background(), refreshToken))
+	assert.True(t, rt.IsValid(context.Background(), newRefreshToken))
+	assert.Equal(t, store.refresh[refreshToken].SessionID, store.refresh[newRefreshToken].SessionID)
+	assert.Equal(t, store.refresh[newRefreshToken].SessionID, store.access[newAccessToken].SessionID)
+	assert.Equal(t, store.access[newAccessToken].ID.String(), store.refresh[newRefreshToken].PairTokenID)
+
+	// Kasus uji pemakaian ulang refresh token lama mencabut seluruh session
+	_, _, err = tokenutil.RotateRefreshToken(refreshToken, "", accessSecret, 1, refreshSecret, 24, uu, at, rt)
+	assert.ErrorIs(t, err, tokenutil.ErrRefreshTokenReused)
+	assert.False(t, rt.IsValid(context.Background(), newRefreshToken))
+	assert.False(t, at.IsValid(context.Background(), newAccessToken))
+
+	// Kasus uji token acak ditolak tanpa dianggap pemakaian ulang
+	_, _, err = tokenutil.RotateRefreshToken("not-a-token", "", accessSecret, 1, refreshSecret, 24, uu, at, rt)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, tokenutil.ErrRefreshTokenReused)
+}
+
+func TestRotateRefreshTokenInactiveUser(t *testing.T) {
+	store := newMemoryTokens()
+	at, rt := accessTokens{store}, refreshTokens{store}
+	user := domain.User{ID: uuid.New(), Name: "Operator", Role: "admin", IsActive: true}
+
+	_, refreshToken, err := tokenutil.CreateTokenPair(&user, accessSecret, 1, refreshSecret, 24, at, rt)
+	assert.NoError(t, err)
+
+	user.IsActive = false
+	_, _, err = tokenutil.RotateRefreshToken(refreshToken, "", accessSecret, 1, refreshSecret, 24, users{user}, at, rt)
+	assert.Error(t, err)
+	assert.True(t, rt.IsValid(context.Background(), refreshToken))
+}
+
+func TestRevokeToken(t *testing.T) {
+	store := newMemoryTokens()
+	at, rt := accessTokens{store}, refreshTokens{store}
+	user := domain.User{ID: uuid.New(), Name: "Operator", Role: "admin", IsActive: true}
+
+	laptopAccess, laptopRefresh, err := tokenutil.CreateTokenPair(&user, accessSecret, 1, refreshSecret, 24, at, rt)
+	assert.NoError(t, err)
+	phoneAccess, phoneRefresh, err := tokenutil.CreateTokenPair(&user, accessSecret, 1, refreshSecret, 24, at, rt)
+	assert.NoError(t, err)
+	assert.Equal(t, store.access[laptopAccess].ID.String(), store.refresh[laptopRefresh].PairTokenID)
+	assert.NotEqual(t, store.access[laptopAccess].SessionID, store.access[phoneAccess].SessionID)
+
+	// Kasus uji logout satu perangkat hanya mencabut token perangkat tersebut
+	assert.NoError(t, tokenutil.RevokeToken(laptopAccess, accessSecret, at, rt))
+	assert.False(t, at.IsValid(context.Background(), laptopAccess))
+	assert.False(t, rt.IsValid(context.Background(), laptopRefresh))
+	assert.True(t, at.IsValid(context.Background(), phoneAccess))
+	assert.True(t, rt.IsValid(context.Background(), phoneRefresh))
+
+	// Kasus uji token yang sudah dicabut tidak dapat dipakai logout lagi
+	assert.Error(t, tokenutil.RevokeToken(laptopAccess, accessSecret, at, rt))
+}
@@ -24,8 +24,10 @@ func GetBaseURL(request *http.Request) string {
 	return baseURL
 }
 
+// RemoveAPIVersionMiddleware membuang prefix versi (/v1/ atau /api/v1/) agar
+// policy casbin berlaku sama untuk route HTML dan API.
 func RemoveAPIVersionMiddleware(pathURL string) string {
-	regex := regexp.MustCompile(`^/v[0-9]+/`)
+	regex := regexp.MustCompile(`^(/api)?/v[0-9]+/`)
 	newPath := regex.ReplaceAllString(pathURL, "/")
 	return newPath
 }
@@ -54,6 +54,10 @@ func TestRemoveAPIVersionMiddleware(t *testing.T) {
 	resultWithAPIVersion := urlutil.RemoveAPIVersionMiddleware(pathURLWithAPIVersion)
 	assert.Equal(t, urlUser, resultWithAPIVersion, errMsgUnexpectedResult)
 
+	// Kasus uji untuk pathURL dengan prefix /api
+	resultWithAPIPrefix := urlutil.RemoveAPIVersionMiddleware("/api/v1/users")
+	assert.Equal(t, urlUser, resultWithAPIPrefix, errMsgUnexpectedResult)
+
 	// Kasus uji untuk pathURL tanpa API version
 	pathURLWithoutAPIVersion := urlUser
 	resultWithoutAPIVersion := urlutil.RemoveAPIVersionMiddleware(pathURLWithoutAPIVersion)
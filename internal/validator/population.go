@@ -0,0 +1,255 @@
+package validator
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/koropati/population-recap/domain"
+)
+
+const (
+	// BangliProvinceCode dan BangliRegencyCode adalah kode Kemendagri untuk
+	// Provinsi Bali dan Kabupaten Bangli yang menjadi awalan NIK dan nomor KK.
+	BangliProvinceCode = "51"
+	BangliRegencyCode  = "06"
+
+	nikLength = 16
+	kkLength  = 16
+
+	femaleDayOffset = 40
+)
+
+// BangliDistrictCodes adalah kode kecamatan di Kabupaten Bangli
+// (Susut, Bangli, Tembuku, Kintamani).
+var BangliDistrictCodes = []string{"01", "02", "03", "04"}
+
+var (
+	ErrInvalidLength    = errors.New("must be 16 digits")
+	ErrNotNumeric       = errors.New("must only contain digits")
+	ErrRegionNotBangli  = errors.New("region prefix is not a Bangli district")
+	ErrInvalidBirthDate = errors.New("encoded birth date is invalid")
+	ErrInvalidIssueDate = errors.New("encoded issue date is invalid")
+	ErrInvalidSerial    = errors.New("serial number can not be 0000")
+	ErrNIKSexMismatch   = errors.New("sex does not match the NIK")
+	ErrNIKBirthMismatch = errors.New("birth date does not match the NIK")
+
+	errInvalidDate = errors.New("invalid date")
+)
+
+// now dapat diganti saat pengujian.
+var now = time.Now
+
+// NIKInfo adalah data yang terkandung di dalam NIK.
+type NIKInfo struct {
+	ProvinceCode string
+	RegencyCode  string
+	DistrictCode string
+	BirthDate    time.Time
+	Sex          string
+	Serial       string
+}
+
+// KKInfo adalah data yang terkandung di dalam nomor Kartu Keluarga.
+type KKInfo struct {
+	ProvinceCode string
+	RegencyCode  string
+	DistrictCode string
+	IssueDate    time.Time
+	Serial       string
+}
+
+// ParseNIK menguraikan NIK dengan format PPKKCC DDMMYY SSSS. Untuk perempuan
+// tanggal lahir ditambah 40 sehingga jenis kelamin dapat diturunkan dari NIK.
+func ParseNIK(nik string) (info NIKInfo, err error) {
+	info, err = ParseNIKFormat(nik)
+	if err != nil {
+		return NIKInfo{}, err
+	}
+	if !IsBangliRegion(info.ProvinceCode, info.RegencyCode, info.DistrictCode) {
+		return NIKInfo{}, ErrRegionNotBangli
+	}
+	return info, nil
+}
+
+// ParseNIKFormat seperti ParseNIK tanpa mensyaratkan kode wilayah Bangli,
+// dipakai untuk NIK yang diterbitkan daerah lain, misalnya penduduk yang
+// datang dari luar Bangli.
+func ParseNIKFormat(nik string) (info NIKInfo, err error) {
+	if err = checkDigits(nik, nikLength); err != nil {
+		return NIKInfo{}, err
+	}
+
+	info.ProvinceCode, info.RegencyCode, info.DistrictCode = nik[0:2], nik[2:4], nik[4:6]
+
+	day, _ := strconv.Atoi(nik[6:8])
+	info.Sex = domain.SexMale
+	if day > femaleDayOffset {
+		day -= femaleDayOffset
+		info.Sex = domain.SexFemale
+	}
+
+	info.BirthDate, err = decodeDate(day, nik[8:10], nik[10:12])
+	if err != nil {
+		return NIKInfo{}, ErrInvalidBirthDate
+	}
+
+	info.Serial = nik[12:16]
+	if info.Serial == "0000" {
+		return NIKInfo{}, ErrInvalidSerial
+	}
+	return info, nil
+}
+
+// ParseKK menguraikan nomor KK dengan format PPKKCC DDMMYY SSSS, di mana
+// DDMMYY adalah tanggal penerbitan kartu.
+func ParseKK(kk string) (info KKInfo, err error) {
+	if err = checkDigits(kk, kkLength); err != nil {
+		return KKInfo{}, err
+	}
+
+	info.ProvinceCode, info.RegencyCode, info.DistrictCode = kk[0:2], kk[2:4], kk[4:6]
+	if !IsBangliRegion(info.ProvinceCode, info.RegencyCode, info.DistrictCode) {
+		return KKInfo{}, ErrRegionNotBangli
+	}
+
+	day, _ := strconv.Atoi(kk[6:8])
+	info.IssueDate, err = decodeDate(day, kk[8:10], kk[10:12])
+	if err != nil {
+		return KKInfo{}, ErrInvalidIssueDate
+	}
+
+	info.Serial = kk[12:16]
+	if info.Serial == "0000" {
+		return KKInfo{}, ErrInvalidSerial
+	}
+	return info, nil
+}
+
+// IsBangliRegion memeriksa apakah kode provinsi/kabupaten/kecamatan berada
+// di Kabupaten Bangli.
+func IsBangliRegion(provinceCode, regencyCode, districtCode string) bool {
+	if provinceCode != BangliProvinceCode || regencyCode != BangliRegencyCode {
+		return false
+	}
+	for _, code := range BangliDistrictCodes {
+		if code == districtCode {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDigits(value string, length int) error {
+	if len(value) != length {
+		return ErrInvalidLength
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return ErrNotNumeric
+		}
+	}
+	return nil
+}
+
+// decodeDate mengubah DD, MM dan YY menjadi tanggal. Tahun dua digit
+// dianggap abad ini kecuali hasilnya melewati hari ini; tanggal yang tidak
+// ada di kalender atau masih di masa depan ditolak.
+func decodeDate(day int, month string, year string) (time.Time, error) {
+	m, _ := strconv.Atoi(month)
+	y, _ := strconv.Atoi(year)
+
+	today := now()
+	fullYear := 2000 + y
+	if fullYear > today.Year() {
+		fullYear -= 100
+	}
+
+	date := time.Date(fullYear, time.Month(m), day, 0, 0, 0, 0, time.UTC)
+	if day < 1 || m < 1 || m > 12 || date.Day() != day || int(date.Month()) != m {
+		return time.Time{}, errInvalidDate
+	}
+	if date.After(today) {
+		return time.Time{}, errInvalidDate
+	}
+	return date, nil
+}
+
+func validateNIK(fl validator.FieldLevel) bool {
+	_, err := ParseNIK(fl.Field().String())
+	return err == nil
+}
+
+// validateNIKFormat dipakai untuk NIK penduduk yang tinggal di Bangli tetapi
+// masih memakai NIK dari daerah asalnya, sehingga prefix wilayah tidak
+// diperiksa.
+func validateNIKFormat(fl validator.FieldLevel) bool {
+	_, err := ParseNIKFormat(fl.Field().String())
+	return err == nil
+}
+
+func validateKK(fl validator.FieldLevel) bool {
+	_, err := ParseKK(fl.Field().String())
+	return err == nil
+}
+
+// validateNIKSex dipakai sebagai `nik_sex=Sex` pada field NIK untuk
+// mencocokkan jenis kelamin di NIK dengan field Sex pada struct yang sama.
+func validateNIKSex(fl validator.FieldLevel) bool {
+	sex, _, _, ok := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+	if !ok || sex.String() == "" {
+		return true
+	}
+	return CheckNIKSex(fl.Field().String(), sex.String()) == nil
+}
+
+// validateNIKBirthDate dipakai sebagai `nik_birth_date=BirthDate` pada field
+// NIK untuk mencocokkan tanggal lahir di NIK dengan field tanggal lahir.
+func validateNIKBirthDate(fl validator.FieldLevel) bool {
+	field, _, _, ok := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+	if !ok {
+		return true
+	}
+	birthDate, isTime := field.Interface().(time.Time)
+	if !isTime || birthDate.IsZero() {
+		return true
+	}
+	return CheckNIKBirthDate(fl.Field().String(), birthDate) == nil
+}
+
+// CheckNIKSex mencocokkan jenis kelamin yang tercatat dengan yang tersandi di NIK.
+func CheckNIKSex(nik string, sex string) error {
+	info, err := ParseNIKFormat(nik)
+	if err != nil {
+		return err
+	}
+	if info.Sex != sex {
+		return ErrNIKSexMismatch
+	}
+	return nil
+}
+
+// CheckNIKBirthDate mencocokkan tanggal lahir yang tercatat dengan yang tersandi di NIK.
+func CheckNIKBirthDate(nik string, birthDate time.Time) error {
+	info, err := ParseNIKFormat(nik)
+	if err != nil {
+		return err
+	}
+	if info.BirthDate.Day() != birthDate.Day() || info.BirthDate.Month() != birthDate.Month() || info.BirthDate.Year()%100 != birthDate.Year()%100 {
+		return ErrNIKBirthMismatch
+	}
+	return nil
+}
+
+// CheckNIKFormat memeriksa format NIK yang diterbitkan daerah mana pun beserta
+// kecocokan jenis kelamin dan tanggal lahirnya.
+func CheckNIKFormat(nik string, sex string, birthDate time.Time) error {
+	if _, err := ParseNIKFormat(nik); err != nil {
+		return err
+	}
+	if err := CheckNIKSex(nik, sex); err != nil {
+		return err
+	}
+	return CheckNIKBirthDate(nik, birthDate)
+}
@@ -0,0 +1,154 @@
+package validator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	nikMale   = "5106021505900002"
+	nikFemale = "5106024505900001"
+	kkValid   = "5106020101150003"
+)
+
+type residentForm struct {
+	NIK       string    `validate:"required,nik,nik_sex=Sex,nik_birth_date=BirthDate"`
+	Sex       string    `validate:"required"`
+	BirthDate time.Time `validate:"required"`
+}
+
+type familyForm struct {
+	KKNumber string `validate:"required,kk"`
+}
+
+func TestParseNIK(t *testing.T) {
+	// Kasus uji NIK laki-laki
+	info, err := validator.ParseNIK(nikMale)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SexMale, info.Sex)
+	assert.Equal(t, time.Date(1990, time.May, 15, 0, 0, 0, 0, time.UTC), info.BirthDate)
+	assert.Equal(t, "02", info.DistrictCode)
+
+	// Kasus uji NIK perempuan (tanggal lahir + 40)
+	info, err = validator.ParseNIK(nikFemale)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SexFemale, info.Sex)
+	assert.Equal(t, 5, info.BirthDate.Day())
+}
+
+func TestParseNIKInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		nik      string
+		expected error
+	}{
+		{
+			name:     "Panjang kurang dari 16 digit",
+			nik:      "510602150590",
+			expected: validator.ErrInvalidLength,
+		},
+		{
+			name:     "Mengandung huruf",
+			nik:      "51060215059A0002",
+			expected: validator.ErrNotNumeric,
+		},
+		{
+			name:     "Bukan kode wilayah Bangli",
+			nik:      "5171021505900002",
+			expected: validator.ErrRegionNotBangli,
+		},
+		{
+			name:     "Kecamatan tidak ada di Bangli",
+			nik:      "5106091505900002",
+			expected: validator.ErrRegionNotBangli,
+		},
+		{
+			name:     "Tanggal 31 Februari",
+			nik:      "5106023102900002",
+			expected: validator.ErrInvalidBirthDate,
+		},
+		{
+			name:     "Tanggal perempuan melebihi 71",
+			nik:      "5106027205900002",
+			expected: validator.ErrInvalidBirthDate,
+		},
+		{
+			name:     "Nomor urut 0000",
+			nik:      "5106021505900000",
+			expected: validator.ErrInvalidSerial,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.ParseNIK(tt.nik)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestParseNIKFormat(t *testing.T) {
+	// Kasus uji NIK dari luar Bangli tetap dapat diuraikan
+	info, err := validator.ParseNIKFormat("5171021505900002")
+	assert.NoError(t, err)
+	assert.Equal(t, "51", info.ProvinceCode)
+	assert.Equal(t, "71", info.RegencyCode)
+	assert.Equal(t, domain.SexMale, info.Sex)
+
+	// Kasus uji format dan tanggal lahir tetap diperiksa
+	_, err = validator.ParseNIKFormat("3171023102900002")
+	assert.ErrorIs(t, err, validator.ErrInvalidBirthDate)
+	_, err = validator.ParseNIKFormat("31710215059A0002")
+	assert.ErrorIs(t, err, validator.ErrNotNumeric)
+}
+
+func TestCheckNIKFormat(t *testing.T) {
+	birthDate := time.Date(1990, time.May, 15, 0, 0, 0, 0, time.UTC)
+
+	// Kasus uji NIK Denpasar dengan jenis kelamin dan tanggal lahir sesuai
+	assert.NoError(t, validator.CheckNIKFormat("5171021505900002", domain.SexMale, birthDate))
+
+	// Kasus uji jenis kelamin dan tanggal lahir tidak sesuai
+	assert.ErrorIs(t, validator.CheckNIKFormat("5171021505900002", domain.SexFemale, birthDate), validator.ErrNIKSexMismatch)
+	assert.ErrorIs(t, validator.CheckNIKFormat("5171021505900002", domain.SexMale, birthDate.AddDate(0, 0, 1)), validator.ErrNIKBirthMismatch)
+}
+
+func TestParseKK(t *testing.T) {
+	info, err := validator.ParseKK(kkValid)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC), info.IssueDate)
+
+	_, err = validator.ParseKK("5106023013150003")
+	assert.ErrorIs(t, err, validator.ErrInvalidIssueDate)
+}
+
+func TestCheckNIKSexAndBirthDate(t *testing.T) {
+	assert.NoError(t, validator.CheckNIKSex(nikFemale, domain.SexFemale))
+	assert.ErrorIs(t, validator.CheckNIKSex(nikFemale, domain.SexMale), validator.ErrNIKSexMismatch)
+
+	assert.NoError(t, validator.CheckNIKBirthDate(nikMale, time.Date(1990, time.May, 15, 0, 0, 0, 0, time.Local)))
+	assert.ErrorIs(t, validator.CheckNIKBirthDate(nikMale, time.Date(1990, time.May, 16, 0, 0, 0, 0, time.UTC)), validator.ErrNIKBirthMismatch)
+}
+
+func TestValidatePopulationTags(t *testing.T) {
+	v := validator.NewValidator()
+	birthDate := time.Date(1990, time.May, 15, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, v.Validate(residentForm{NIK: nikMale, Sex: domain.SexMale, BirthDate: birthDate}))
+	assert.Error(t, v.Validate(residentForm{NIK: nikMale, Sex: domain.SexFemale, BirthDate: birthDate}), "jenis kelamin tidak cocok")
+	assert.Error(t, v.Validate(residentForm{NIK: nikMale, Sex: domain.SexMale, BirthDate: birthDate.AddDate(0, 0, 1)}), "tanggal lahir tidak cocok")
+
+	assert.NoError(t, v.Validate(familyForm{KKNumber: kkValid}))
+	assert.Error(t, v.Validate(familyForm{KKNumber: "1234"}))
+
+	assert.NoError(t, v.Var(nikFemale, "nik"))
+	assert.Error(t, v.Var("5171021505900002", "nik"))
+
+	// Kasus uji nik_format menerima NIK dari luar Bangli
+	assert.NoError(t, v.Var("5171021505900002", "nik_format"))
+	assert.Error(t, v.Var("3171023102900002", "nik_format"))
+}
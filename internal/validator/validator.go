@@ -9,10 +9,14 @@ type Validator struct {
 	validate *validator.Validate
 }
 
-// NewValidator membuat dan mengembalikan instance baru dari Validator.
+// NewValidator membuat dan mengembalikan instance baru dari Validator
+// beserta tag khusus kependudukan (nik, nik_format, kk, nik_sex,
+// nik_birth_date).
 func NewValidator() *Validator {
+	validate := validator.New()
+	registerPopulationTags(validate)
 	return &Validator{
-		validate: validator.New(),
+		validate: validate,
 	}
 }
 
@@ -20,3 +24,22 @@ func NewValidator() *Validator {
 func (v *Validator) Validate(i interface{}) error {
 	return v.validate.Struct(i)
 }
+
+// ValidateExcept memvalidasi struct tanpa field yang disebutkan, misalnya
+// field yang tidak dapat diubah saat update.
+func (v *Validator) ValidateExcept(i interface{}, fields ...string) error {
+	return v.validate.StructExcept(i, fields...)
+}
+
+// Var digunakan untuk memvalidasi satu nilai berdasarkan tag, misalnya Var(nik, "nik").
+func (v *Validator) Var(field interface{}, tag string) error {
+	return v.validate.Var(field, tag)
+}
+
+func registerPopulationTags(validate *validator.Validate) {
+	_ = validate.RegisterValidation("nik", validateNIK)
+	_ = validate.RegisterValidation("nik_format", validateNIKFormat)
+	_ = validate.RegisterValidation("kk", validateKK)
+	_ = validate.RegisterValidation("nik_sex", validateNIKSex)
+	_ = validate.RegisterValidation("nik_birth_date", validateNIKBirthDate)
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+)
+
+// AuditActorMiddleware menyimpan pengguna yang sudah masuk beserta alamat IP
+// asal request di context sehingga setiap perubahan data kependudukan
+// tercatat atas nama pengguna tersebut. Dipasang setelah AuthMiddleware atau
+// JwtAuthMiddleware.
+func AuditActorMiddleware(cryptos cryptos.Cryptos) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, userRole := GetUserContext(c, cryptos)
+		c.Set(domain.AuditActorContext, domain.AuditActor{
+			UserID:    userID,
+			Role:      userRole,
+			IPAddress: c.ClientIP(),
+		})
+		c.Next()
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"net/http"
 
 	"github.com/casbin/casbin"
+	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/bootstrap"
 	"github.com/koropati/population-recap/domain"
 	"github.com/koropati/population-recap/internal/cryptos"
 	"github.com/koropati/population-recap/internal/tokenutil"
@@ -15,22 +17,28 @@ const (
 	DashboardUrlRedirect = "/dashboard"
 )
 
-func AuthMiddleware(secret string, casbinEnforcer *casbin.Enforcer, cryptos cryptos.Cryptos, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) gin.HandlerFunc {
+// AuthMiddleware memeriksa access token pada session. Access token yang
+// kedaluwarsa ditukar otomatis memakai refresh token pada session agar
+// petugas tidak keluar di tengah pengisian data.
+func AuthMiddleware(config *bootstrap.Config, casbinEnforcer *casbin.SyncedEnforcer, cryptos cryptos.Cryptos, userUsecase domain.UserUsecase, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authToken, err := GetAuthContext(c, cryptos, "access")
 		if err != nil {
-			c.Redirect(http.StatusFound, LoginUrlRedirect)
+			redirectToLogin(c)
 			return
 		}
 
 		if !accessTokenUsecase.IsValid(c, authToken) {
-			c.Redirect(http.StatusFound, LoginUrlRedirect)
-			return
+			authToken, err = refreshSession(c, config, cryptos, authToken, userUsecase, accessTokenUsecase, refreshTokenUsecase)
+			if err != nil {
+				redirectToLogin(c)
+				return
+			}
 		}
 
-		userID, userRole, err := tokenutil.ExtractIDFromToken(authToken, secret, AccessToken, accessTokenUsecase, refreshTokenUsecase)
+		userID, userRole, err := tokenutil.ExtractIDFromToken(authToken, config.AccessTokenSecret, AccessToken, accessTokenUsecase, refreshTokenUsecase)
 		if err != nil {
-			c.Redirect(http.StatusFound, LoginUrlRedirect)
+			redirectToLogin(c)
 			return
 		}
 
@@ -42,6 +50,7 @@ func AuthMiddleware(secret string, casbinEnforcer *casbin.Enforcer, cryptos cryp
 
 		if err := enforceCasbinRules(c, casbinEnforcer, userRole); err != nil {
 			c.Redirect(http.StatusFound, LoginUrlRedirect)
+			c.Abort()
 			return
 		}
 
@@ -49,7 +58,36 @@ func AuthMiddleware(secret string, casbinEnforcer *casbin.Enforcer, cryptos cryp
 	}
 }
 
-func AuthPublicMiddleware(secret string, casbinEnforcer *casbin.Enforcer, cryptos cryptos.Cryptos, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) gin.HandlerFunc {
+// refreshSession merotasi pasangan token pada session dan mengembalikan
+// access token yang baru.
+func refreshSession(c *gin.Context, config *bootstrap.Config, cryptos cryptos.Cryptos, accessToken string, userUsecase domain.UserUsecase, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) (string, error) {
+	refreshToken, err := GetAuthContext(c, cryptos, "refresh")
+	if err != nil {
+		return "", err
+	}
+
+	newAccessToken, newRefreshToken, err := tokenutil.RotateRefreshToken(refreshToken, accessToken, config.AccessTokenSecret, config.AccessTokenExpiryHour, config.RefreshTokenSecret, config.RefreshTokenExpiryHour, userUsecase, accessTokenUsecase, refreshTokenUsecase)
+	if err != nil {
+		return "", err
+	}
+
+	if err := SetAuthContext(c, cryptos, newAccessToken, newRefreshToken); err != nil {
+		return "", err
+	}
+	return newAccessToken, nil
+}
+
+// redirectToLogin mengosongkan session yang tidak berlaku lagi agar
+// AuthPublicMiddleware tidak mengembalikan pengguna ke dashboard.
+func redirectToLogin(c *gin.Context) {
+	session := sessions.Default(c)
+	session.Clear()
+	session.Save()
+	c.Redirect(http.StatusFound, LoginUrlRedirect)
+	c.Abort()
+}
+
+func AuthPublicMiddleware(secret string, casbinEnforcer *casbin.SyncedEnforcer, cryptos cryptos.Cryptos, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authToken, _ := GetAuthContext(c, cryptos, "access")
 		if c.Request.URL.Path == "/" || c.Request.URL.Path == "/logout" {
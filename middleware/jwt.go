@@ -28,7 +28,7 @@ const (
 	AccessToken        = "access_token"
 )
 
-func JwtAuthMiddleware(secret string, casbinEnforcer *casbin.Enforcer, cryptos cryptos.Cryptos, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) gin.HandlerFunc {
+func JwtAuthMiddleware(secret string, casbinEnforcer *casbin.SyncedEnforcer, cryptos cryptos.Cryptos, accessTokenUsecase domain.AccessTokenUsecase, refreshTokenUsecase domain.RefreshTokenUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.Request.Header.Get("Authorization")
 		authToken, err := parseAuthorizationHeader(authHeader)
@@ -130,7 +130,12 @@ func GetUserContext(c *gin.Context, cryptos cryptos.Cryptos) (userID string, use
 	if err != nil {
 		panic(err)
 	}
-	userRole = strings.Split(userRoleWithGarbage, "_")[0]
+	// Garbage ditambahkan di belakang garis bawah terakhir, peran seperti
+	// super_admin sendiri mengandung garis bawah.
+	if i := strings.LastIndex(userRoleWithGarbage, "_"); i >= 0 {
+		userRoleWithGarbage = userRoleWithGarbage[:i]
+	}
+	userRole = userRoleWithGarbage
 	return userID, userRole
 }
 
@@ -156,7 +161,7 @@ func GetAuthContext(c *gin.Context, cryptos cryptos.Cryptos, tokenType string) (
 	return token, nil
 }
 
-func enforceCasbinRules(c *gin.Context, casbinEnforcer *casbin.Enforcer, userRole string) *domain.JsonResponse {
+func enforceCasbinRules(c *gin.Context, casbinEnforcer *casbin.SyncedEnforcer, userRole string) *domain.JsonResponse {
 	pathUrl := urlutil.RemoveAPIVersionMiddleware(c.Request.URL.Path)
 	res, err := casbinEnforcer.EnforceSafe(userRole, pathUrl, c.Request.Method)
 	if err != nil {
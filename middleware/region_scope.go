@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/cryptos"
+	"gorm.io/gorm"
+)
+
+// RegionScopeMiddleware memuat wilayah tugas pengguna yang sudah masuk dan
+// menyimpannya di context sehingga seluruh query data kependudukan otomatis
+// dibatasi pada wilayah tersebut. Dipasang setelah AuthMiddleware atau
+// JwtAuthMiddleware, pengguna yang tidak dikenali tidak melihat data apa pun.
+// Peran dibaca dari data pengguna, bukan dari token, sehingga perubahan peran
+// langsung berlaku dan cakupan tidak bergantung pada penguraian peran di
+// session atau token.
+func RegionScopeMiddleware(cryptos cryptos.Cryptos, userUsecase domain.UserUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := GetUserContext(c, cryptos)
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			c.Set(domain.RegionScopeContext, domain.RegionScope{Restricted: true})
+			c.Next()
+			return
+		}
+
+		user, err := userUsecase.GetById(c, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Set(domain.RegionScopeContext, domain.RegionScope{Restricted: true})
+			c.Next()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, domain.JsonResponse{Message: err.Error(), Success: false})
+			c.Abort()
+			return
+		}
+
+		regions, err := userUsecase.GetRegions(c, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, domain.JsonResponse{Message: err.Error(), Success: false})
+			c.Abort()
+			return
+		}
+
+		c.Set(domain.RegionScopeContext, domain.NewRegionScope(user.Role, regions))
+		c.Next()
+	}
+}
@@ -34,6 +34,14 @@ func (r *accessTokenRepository) Create(c context.Context, accessToken domain.Acc
 	return nil
 }
 
+func (r *accessTokenRepository) GetByToken(c context.Context, token string) (accessToken domain.AccessToken, err error) {
+	result := r.database.WithContext(c).Table(r.table).Where("token = ?", token).First(&accessToken)
+	if result.Error != nil {
+		return domain.AccessToken{}, result.Error
+	}
+	return accessToken, nil
+}
+
 func (r *accessTokenRepository) Revoke(c context.Context, token string) error {
 	result := r.database.WithContext(c).Table(r.table).Where("token = ?", token).Update("revoked", true)
 	if result.Error != nil {
@@ -45,8 +53,8 @@ func (r *accessTokenRepository) Revoke(c context.Context, token string) error {
 	return nil
 }
 
-func (r *accessTokenRepository) RevokeByUserID(c context.Context, userID uuid.UUID) error {
-	result := r.database.WithContext(c).Table(r.table).Where("user_id = ?", userID).Update("revoked", true)
+func (r *accessTokenRepository) RevokeByID(c context.Context, id uuid.UUID) error {
+	result := r.database.WithContext(c).Table(r.table).Where(queryFindByID, id).Update("revoked", true)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -56,6 +64,19 @@ func (r *accessTokenRepository) RevokeByUserID(c context.Context, userID uuid.UU
 	return nil
 }
 
+func (r *accessTokenRepository) RevokeBySessionID(c context.Context, sessionID uuid.UUID) error {
+	return r.database.WithContext(c).Table(r.table).Where("session_id = ?", sessionID).Update("revoked", true).Error
+}
+
+// RevokeByUserID tidak menganggap pengguna tanpa token sebagai kesalahan.
+func (r *accessTokenRepository) RevokeByUserID(c context.Context, userID uuid.UUID) error {
+	result := r.database.WithContext(c).Table(r.table).Where("user_id = ?", userID).Update("revoked", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 func (r *accessTokenRepository) IsValid(c context.Context, token string) bool {
 	var accessToken domain.AccessToken
 	result := r.database.WithContext(c).Table(r.table).Where("token = ? AND revoked = ?", token, false).First(&accessToken)
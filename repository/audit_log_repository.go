@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+type auditLogRepository struct {
+	database  *gorm.DB
+	table     string
+	pageInit  int64
+	limitInit int64
+	builder   queryBuilder
+}
+
+func NewAuditLogRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.AuditLogRepository {
+	return &auditLogRepository{
+		database:  db,
+		table:     table,
+		pageInit:  pageInit,
+		limitInit: limitInit,
+		builder: queryBuilder{
+			search: []searchColumn{{"entity_id", searchPrefix}},
+			filters: map[string]fieldFilter{
+				"actor_role": {"actor_role", filterIn},
+			},
+			sorts: map[string]string{
+				"created_at": "created_at",
+				"entity":     "entity",
+				"action":     "action",
+			},
+			defaultSort:  "created_at",
+			defaultOrder: "desc",
+			tiebreaker:   []string{"id"},
+			pageInit:     pageInit,
+			limitInit:    limitInit,
+		},
+	}
+}
+
+func (r *auditLogRepository) Retrieve(c context.Context, filter domain.AuditLogFilter) (logs []domain.AuditLog, meta domain.MetaResponse, err error) {
+	conditions := func(db *gorm.DB) *gorm.DB {
+		if filter.Entity != "" {
+			db = db.Where("entity = ?", filter.Entity)
+		}
+		if filter.Action != "" {
+			db = db.Where("action = ?", filter.Action)
+		}
+		if filter.ActorID != "" {
+			db = db.Where("actor_id = ?", filter.ActorID)
+		}
+		if !filter.From.IsZero() {
+			db = db.Where("created_at >= ?", filter.From.Unix())
+		}
+		if !filter.To.IsZero() {
+			db = db.Where("created_at < ?", filter.To.AddDate(0, 0, 1).Unix())
+		}
+		return db
+	}
+
+	meta, err = r.builder.retrieve(r.database.WithContext(c).Table(r.table).Model(&domain.AuditLog{}), filter.Filter, &logs, conditions)
+	if err != nil {
+		return nil, domain.MetaResponse{}, err
+	}
+	return logs, meta, nil
+}
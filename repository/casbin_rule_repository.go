@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+type casbinRuleRepository struct {
+	database  *gorm.DB
+	table     string
+	pageInit  int64
+	limitInit int64
+	builder   queryBuilder
+}
+
+func NewCasbinRuleRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.CasbinRuleRepository {
+	return &casbinRuleRepository{
+		database:  db,
+		table:     table,
+		pageInit:  pageInit,
+		limitInit: limitInit,
+		builder: queryBuilder{
+			search: []searchColumn{{"v0", searchContains}, {"v1", searchContains}},
+			filters: map[string]fieldFilter{
+				"p_type": {"p_type", filterEquals},
+				"v0":     {"v0", filterIn},
+				"v2":     {"v2", filterIn},
+			},
+			sorts: map[string]string{
+				"p_type": "p_type",
+				"v0":     "v0",
+				"v1":     "v1",
+			},
+			defaultSort:  "p_type",
+			defaultOrder: "desc",
+			tiebreaker:   []string{"v0", "v1", "v2", "id"},
+			pageInit:     pageInit,
+			limitInit:    limitInit,
+		},
+	}
+}
+
+func (r *casbinRuleRepository) Create(c context.Context, rule *domain.CasbinRule) error {
+	result := r.database.WithContext(c).Table(r.table).Create(rule)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *casbinRuleRepository) Retrieve(c context.Context, filter domain.Filter) (rules []domain.CasbinRule, meta domain.MetaResponse, err error) {
+	userRole := func(db *gorm.DB) *gorm.DB {
+		if filter.UserRole == "" {
+			return db
+		}
+		return db.Where("v0 = ?", filter.UserRole)
+	}
+
+	meta, err = r.builder.retrieve(r.database.WithContext(c).Table(r.table).Model(&domain.CasbinRule{}), filter, &rules, userRole)
+	if err != nil {
+		return nil, domain.MetaResponse{}, err
+	}
+	return rules, meta, nil
+}
+
+func (r *casbinRuleRepository) GetById(c context.Context, id uint) (rule domain.CasbinRule, err error) {
+	result := r.database.WithContext(c).Table(r.table).Where(queryFindByID, id).First(&rule)
+	if result.Error != nil {
+		return domain.CasbinRule{}, result.Error
+	}
+	return rule, nil
+}
+
+func (r *casbinRuleRepository) Exists(c context.Context, rule domain.CasbinRule) (bool, error) {
+	var count int64
+	err := r.database.WithContext(c).Table(r.table).
+		Where("p_type = ? AND v0 = ? AND v1 = ? AND v2 = ? AND v3 = ? AND v4 = ? AND v5 = ?", rule.PType, rule.V0, rule.V1, rule.V2, rule.V3, rule.V4, rule.V5).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *casbinRuleRepository) Delete(c context.Context, id uint) error {
+	result := r.database.WithContext(c).Table(r.table).Where(queryFindByID, id).Delete(&domain.CasbinRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no policy was deleted")
+	}
+	return nil
+}
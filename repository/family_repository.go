@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+const (
+	preloadFamilyMembers        = "Members"
+	preloadFamilyMemberResident = "Members.Resident"
+	queryCurrentMember          = "valid_until IS NULL"
+	// queryActiveFamily mengabaikan keanggotaan pada KK yang terhapus.
+	queryActiveFamily = "family_id IN (SELECT id FROM " + domain.FamilyTable + " WHERE deleted_at IS NULL)"
+)
+
+type familyRepository struct {
+	database    *gorm.DB
+	table       string
+	memberTable string
+	pageInit    int64
+	limitInit   int64
+	builder     queryBuilder
+}
+
+func NewFamilyRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.FamilyRepository {
+	return &familyRepository{
+		database:    db,
+		table:       table,
+		memberTable: domain.FamilyMemberTable,
+		pageInit:    pageInit,
+		limitInit:   limitInit,
+		builder: queryBuilder{
+			search: []searchColumn{{"kk_number", searchPrefix}, {"address", searchContains}},
+			filters: map[string]fieldFilter{
+				"region_id":  {"region_id", filterIn},
+				"region":     {"region_id", filterRegion},
+				"head_id":    {"head_id", filterEquals},
+				"rt":         {"rt", filterEquals},
+				"rw":         {"rw", filterEquals},
+				"created_at": {"created_at", filterRange},
+			},
+			sorts: map[string]string{
+				"kk_number":  "kk_number",
+				"address":    "address",
+				"created_at": "created_at",
+				"updated_at": "updated_at",
+			},
+			defaultSort: "kk_number",
+			tiebreaker:  []string{"id"},
+			pageInit:    pageInit,
+			limitInit:   limitInit,
+		},
+	}
+}
+
+func (r *familyRepository) Create(c context.Context, data domain.Family) error {
+	return r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		members := data.Members
+		residentIDs := make([]uuid.UUID, 0, len(members))
+		for _, member := range members {
+			residentIDs = append(residentIDs, member.ResidentID)
+		}
+		if err := lockNewMembers(tx, residentIDs); err != nil {
+			return err
+		}
+		if err := tx.Table(r.table).Omit("Members").Create(&data).Error; err != nil {
+			return err
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		return tx.Table(r.memberTable).Omit("Resident").Create(&members).Error
+	})
+}
+
+func (r *familyRepository) Retrieve(c context.Context, filter domain.Filter) (families []domain.Family, meta domain.MetaResponse, err error) {
+	asOf := func(db *gorm.DB) *gorm.DB {
+		if filter.AsOf.IsZero() {
+			return db
+		}
+		return db.Where("EXISTS (SELECT 1 FROM "+r.memberTable+" AS m WHERE m.family_id = "+r.table+".id AND "+validAt("m")+")", filter.AsOf, filter.AsOf)
+	}
+
+	meta, err = r.builder.retrieve(r.scoped(c), filter, &families, asOf)
+	if err != nil {
+		return nil, domain.MetaResponse{}, err
+	}
+	return families, meta, nil
+}
+
+func (r *familyRepository) GetById(c context.Context, id uuid.UUID) (family domain.Family, err error) {
+	result := r.scoped(c).Preload(preloadFamilyMembers, queryCurrentMember).Preload(preloadFamilyMemberResident).Where(queryFindByID, id).First(&family)
+	if result.Error != nil {
+		return domain.Family{}, result.Error
+	}
+	return family, nil
+}
+
+func (r *familyRepository) GetByKKNumber(c context.Context, kkNumber string) (family domain.Family, err error) {
+	result := r.scoped(c).Preload(preloadFamilyMembers, queryCurrentMember).Preload(preloadFamilyMemberResident).Where("kk_number = ?", kkNumber).First(&family)
+	if result.Error != nil {
+		return domain.Family{}, result.Error
+	}
+	return family, nil
+}
+
+func (r *familyRepository) Update(c context.Context, id uuid.UUID, data domain.Family) (family domain.Family, err error) {
+	result := r.scoped(c).Where(queryFindByID, id).Omit("id", "head_id", "Members").Updates(data)
+	if result.Error != nil {
+		return domain.Family{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.Family{}, errors.New("no family was updated")
+	}
+	return r.GetById(c, id)
+}
+
+// Delete hanya menandai KK terhapus. Keanggotaan tetap disimpan agar ikut
+// kembali saat KK dipulihkan, sementara itu anggotanya dapat bergabung ke KK
+// lain karena keanggotaan pada KK terhapus diabaikan.
+func (r *familyRepository) Delete(c context.Context, id uuid.UUID) error {
+	result := r.scoped(c).Where(queryFindByID, id).Delete(&domain.Family{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no family was deleted")
+	}
+	return nil
+}
+
+// Restore menolak memulihkan KK bila salah satu anggotanya sudah bergabung ke
+// KK lain selama KK ini terhapus.
+func (r *familyRepository) Restore(c context.Context, id uuid.UUID) error {
+	return r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var moved int64
+		err := tx.Table(r.memberTable).
+			Where("family_id <> ? AND "+queryCurrentMember, id).
+			Where("resident_id IN (SELECT resident_id FROM "+r.memberTable+" WHERE family_id = ? AND "+queryCurrentMember+")", id).
+			Where(queryActiveFamily).
+			Count(&moved).Error
+		if err != nil {
+			return err
+		}
+		if moved > 0 {
+			return errors.New("a member of this family has joined another family")
+		}
+
+		result := scopeRegion(c, tx.Unscoped().Table(r.table).Model(&domain.Family{}), r.table+".region_id").
+			Where(queryFindByID+" AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no deleted family was restored")
+		}
+		return nil
+	})
+}
+
+// Purge menghapus permanen KK beserta seluruh riwayat keanggotaannya.
+func (r *familyRepository) Purge(c context.Context, before time.Time) (purged int64, err error) {
+	err = r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Unscoped().Table(r.table).Where("deleted_at < ?", before).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := tx.Table(r.memberTable).Where("family_id IN ?", ids).Delete(&domain.FamilyMember{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Table(r.table).Where("id IN ?", ids).Delete(&domain.Family{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+func (r *familyRepository) AddMember(c context.Context, member domain.FamilyMember) error {
+	return r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if err := lockNewMembers(tx, []uuid.UUID{member.ResidentID}); err != nil {
+			return err
+		}
+		return tx.Table(r.memberTable).Omit("Resident").Create(&member).Error
+	})
+}
+
+func (r *familyRepository) RemoveMember(c context.Context, familyID uuid.UUID, residentID uuid.UUID, until time.Time) error {
+	result := r.database.WithContext(c).Table(r.memberTable).
+		Where("family_id = ? AND resident_id = ? AND "+queryCurrentMember, familyID, residentID).
+		Update("valid_until", until)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no family member was removed")
+	}
+	return nil
+}
+
+func (r *familyRepository) GetMemberByResidentID(c context.Context, residentID uuid.UUID) (member domain.FamilyMember, err error) {
+	result := r.database.WithContext(c).Table(r.memberTable).
+		Where("resident_id = ? AND "+queryCurrentMember, residentID).
+		Where(queryActiveFamily).
+		First(&member)
+	if result.Error != nil {
+		return domain.FamilyMember{}, result.Error
+	}
+	return member, nil
+}
+
+// scoped membatasi query KK pada cakupan wilayah pengguna.
+func (r *familyRepository) scoped(c context.Context) *gorm.DB {
+	return scopeRegion(c, r.database.WithContext(c).Table(r.table).Model(&domain.Family{}), r.table+".region_id")
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	preloadMigrationResidents = "Mutations.Resident"
+	preloadOriginRegion       = "OriginRegion"
+	preloadDestinationRegion  = "DestinationRegion"
+)
+
+type migrationRepository struct {
+	database      *gorm.DB
+	table         string
+	mutationTable string
+	residentTable string
+	familyTable   string
+	counterTable  string
+	pageInit      int64
+	limitInit     int64
+	builder       queryBuilder
+}
+
+func NewMigrationRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.MigrationRepository {
+	return &migrationRepository{
+		database:      db,
+		table:         table,
+		mutationTable: domain.MutationTable,
+		residentTable: domain.ResidentTable,
+		familyTable:   domain.FamilyTable,
+		counterTable:  domain.CertificateCounterTable,
+		pageInit:      pageInit,
+		limitInit:     limitInit,
+		builder: queryBuilder{
+			search: []searchColumn{{"certificate_number", searchContains}, {"kind", searchExact}},
+			filters: map[string]fieldFilter{
+				"kind":                  {"kind", filterIn},
+				"family_id":             {"family_id", filterEquals},
+				"origin_region_id":      {"origin_region_id", filterIn},
+				"destination_region_id": {"destination_region_id", filterIn},
+				"move_date":             {"move_date", filterDateRange},
+				"created_at":            {"created_at", filterRange},
+			},
+			sorts: map[string]string{
+				"move_date":          "move_date",
+				"certificate_number": "certificate_number",
+				"created_at":         "created_at",
+			},
+			defaultSort:  "move_date",
+			defaultOrder: "desc",
+			tiebreaker:   []string{"id"},
+			preloads:     []string{preloadOriginRegion, preloadDestinationRegion},
+			pageInit:     pageInit,
+			limitInit:    limitInit,
+		},
+	}
+}
+
+func (r *migrationRepository) Create(c context.Context, migration *domain.Migration, arrivals []domain.Resident) error {
+	return r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		number, err := r.nextCertificateNumber(tx, *migration)
+		if err != nil {
+			return err
+		}
+		migration.CertificateNumber = number
+
+		movers := make([]uuid.UUID, 0, len(migration.Mutations))
+		for _, mutation := range migration.Mutations {
+			if mutation.Type == domain.MutationTypeMoveOut {
+				movers = append(movers, mutation.ResidentID)
+			}
+		}
+
+		switch migration.Kind {
+		case domain.MigrationKindInternal:
+			if err := r.moveResidents(tx, *migration, movers); err != nil {
+				return err
+			}
+		case domain.MigrationKindDeparture:
+			if err := r.departResidents(tx, *migration, movers); err != nil {
+				return err
+			}
+		case domain.MigrationKindArrival:
+			if len(arrivals) > 0 {
+				if err := tx.Table(r.residentTable).Create(&arrivals).Error; err != nil {
+					return err
+				}
+			}
+			for _, resident := range arrivals {
+				if err := openResidencePeriod(tx, resident.ID, resident.RegionID, migration.MoveDate); err != nil {
+					return err
+				}
+			}
+		default:
+			return errors.New("unknown migration kind")
+		}
+
+		mutations := migration.Mutations
+		if err := tx.Table(r.table).Omit("Mutations", "OriginRegion", "DestinationRegion").Create(migration).Error; err != nil {
+			return err
+		}
+		if err := tx.Table(r.mutationTable).Omit("Resident").Create(&mutations).Error; err != nil {
+			return err
+		}
+		migration.Mutations = mutations
+		return nil
+	})
+}
+
+// moveResidents memindahkan penduduk ke wilayah tujuan dengan mengakhiri masa
+// tinggal lama dan membuka masa tinggal baru. Pindah satu KK ikut memindahkan
+// KK-nya, pindah perorangan mengakhiri keanggotaan KK lama.
+func (r *migrationRepository) moveResidents(tx *gorm.DB, migration domain.Migration, residentIDs []uuid.UUID) error {
+	result := tx.Table(r.residentTable).
+		Where("id IN ? AND status = ?", residentIDs, domain.ResidentStatusActive).
+		Update("region_id", migration.DestinationRegionID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(residentIDs)) {
+		return errors.New("some residents are no longer active")
+	}
+
+	if err := closeResidencePeriods(tx, residentIDs, migration.MoveDate); err != nil {
+		return err
+	}
+	for _, id := range residentIDs {
+		if err := openResidencePeriod(tx, id, *migration.DestinationRegionID, migration.MoveDate); err != nil {
+			return err
+		}
+	}
+
+	if migration.FamilyID != nil {
+		changes := map[string]interface{}{"region_id": migration.DestinationRegionID}
+		if migration.DestinationAddress != "" {
+			changes["address"] = migration.DestinationAddress
+		}
+		return tx.Table(r.familyTable).Where(queryFindByID, migration.FamilyID).Updates(changes).Error
+	}
+	return closeMemberships(tx, residentIDs, migration.MoveDate)
+}
+
+// departResidents menandai penduduk pindah ke luar Bangli serta mengakhiri
+// masa tinggal dan keanggotaan KK-nya tanpa menghapus data agar rekap bulan
+// sebelumnya tetap menghitungnya.
+func (r *migrationRepository) departResidents(tx *gorm.DB, migration domain.Migration, residentIDs []uuid.UUID) error {
+	result := tx.Table(r.residentTable).
+		Where("id IN ? AND status = ?", residentIDs, domain.ResidentStatusActive).
+		Updates(map[string]interface{}{
+			"status":         domain.ResidentStatusMovedOut,
+			"departure_date": migration.MoveDate,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(residentIDs)) {
+		return errors.New("some residents are no longer active")
+	}
+
+	if err := closeResidencePeriods(tx, residentIDs, migration.MoveDate); err != nil {
+		return err
+	}
+	return closeMemberships(tx, residentIDs, migration.MoveDate)
+}
+
+// nextCertificateNumber menerbitkan nomor surat berurutan per bulan dengan
+// format PREFIX/TAHUN/BULAN/URUT, misalnya SKP/2024/05/0007.
+func (r *migrationRepository) nextCertificateNumber(tx *gorm.DB, migration domain.Migration) (string, error) {
+	prefix := domain.CertificatePrefixMove
+	if migration.Kind == domain.MigrationKindArrival {
+		prefix = domain.CertificatePrefixArrival
+	}
+	period := fmt.Sprintf("%s/%04d/%02d/", prefix, migration.MoveDate.Year(), int(migration.MoveDate.Month()))
+
+	// Penghitung bulan baru dimulai dari nomor terbesar yang sudah terbit.
+	// Bila dua migrasi membuatnya bersamaan hanya satu yang tersimpan dan
+	// keduanya lalu bergantian mengunci baris yang sama.
+	var issued int64
+	err := tx.Table(r.table).
+		Where("certificate_number LIKE ?", period+"%").
+		Select("COALESCE(MAX(CAST(SUBSTRING_INDEX(certificate_number, '/', -1) AS UNSIGNED)), 0)").
+		Scan(&issued).Error
+	if err != nil {
+		return "", err
+	}
+	counter := domain.CertificateCounter{Period: period, Last: issued}
+	if err := tx.Table(r.counterTable).Clauses(clause.OnConflict{DoNothing: true}).Create(&counter).Error; err != nil {
+		return "", err
+	}
+
+	err = tx.Table(r.counterTable).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("period = ?", period).
+		First(&counter).Error
+	if err != nil {
+		return "", err
+	}
+	counter.Last++
+	if err := tx.Table(r.counterTable).Where("period = ?", period).Update("last", counter.Last).Error; err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%04d", period, counter.Last), nil
+}
+
+func (r *migrationRepository) Retrieve(c context.Context, filter domain.Filter) (migrations []domain.Migration, meta domain.MetaResponse, err error) {
+	asOf := func(db *gorm.DB) *gorm.DB {
+		if filter.AsOf.IsZero() {
+			return db
+		}
+		return db.Where("move_date <= ?", filter.AsOf)
+	}
+
+	meta, err = r.builder.retrieve(r.scoped(c), filter, &migrations, asOf)
+	if err != nil {
+		return nil, domain.MetaResponse{}, err
+	}
+	return migrations, meta, nil
+}
+
+func (r *migrationRepository) GetById(c context.Context, id uuid.UUID) (migration domain.Migration, err error) {
+	result := r.preload(c).Where(queryFindByID, id).First(&migration)
+	if result.Error != nil {
+		return domain.Migration{}, result.Error
+	}
+	return migration, nil
+}
+
+func (r *migrationRepository) GetByCertificateNumber(c context.Context, certificateNumber string) (migration domain.Migration, err error) {
+	result := r.preload(c).Where("certificate_number = ?", certificateNumber).First(&migration)
+	if result.Error != nil {
+		return domain.Migration{}, result.Error
+	}
+	return migration, nil
+}
+
+func (r *migrationRepository) preload(c context.Context) *gorm.DB {
+	return r.scoped(c).
+		Preload(preloadMigrationResidents).
+		Preload(preloadOriginRegion).
+		Preload(preloadDestinationRegion)
+}
+
+// scoped membatasi query pindah/datang pada peristiwa yang wilayah asal atau
+// tujuannya berada di dalam cakupan wilayah pengguna sehingga desa asal dan
+// desa tujuan sama-sama dapat melihat surat pindahnya.
+func (r *migrationRepository) scoped(c context.Context) *gorm.DB {
+	db := r.database.WithContext(c).Table(r.table)
+	origin, args, restricted := regionScopeCondition(c, r.table+".origin_region_id")
+	if !restricted {
+		return db
+	}
+	destination, destinationArgs, _ := regionScopeCondition(c, r.table+".destination_region_id")
+	return db.Where("("+origin+" OR "+destination+")", append(args, destinationArgs...)...)
+}
@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+type mutationRepository struct {
+	database      *gorm.DB
+	table         string
+	residentTable string
+	memberTable   string
+	familyTable   string
+	pageInit      int64
+	limitInit     int64
+	builder       queryBuilder
+}
+
+func NewMutationRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.MutationRepository {
+	return &mutationRepository{
+		database:      db,
+		table:         table,
+		residentTable: domain.ResidentTable,
+		memberTable:   domain.FamilyMemberTable,
+		familyTable:   domain.FamilyTable,
+		pageInit:      pageInit,
+		limitInit:     limitInit,
+		builder: queryBuilder{
+			search: []searchColumn{{"type", searchExact}, {"notes", searchContains}},
+			filters: map[string]fieldFilter{
+				"type":         {"type", filterIn},
+				"cause":        {"cause", filterIn},
+				"region_id":    {"region_id", filterIn},
+				"region":       {"region_id", filterRegion},
+				"resident_id":  {"resident_id", filterEquals},
+				"migration_id": {"migration_id", filterEquals},
+				"event_date":   {"event_date", filterDateRange},
+				"created_at":   {"created_at", filterRange},
+			},
+			sorts: map[string]string{
+				"event_date": "event_date",
+				"type":       "type",
+				"created_at": "created_at",
+			},
+			defaultSort:  "event_date",
+			defaultOrder: "desc",
+			tiebreaker:   []string{"id"},
+			pageInit:     pageInit,
+			limitInit:    limitInit,
+		},
+	}
+}
+
+func (r *mutationRepository) Create(c context.Context, data domain.Mutation) error {
+	result := r.database.WithContext(c).Table(r.table).Create(&data)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *mutationRepository) RegisterBirth(c context.Context, resident domain.Resident, member domain.FamilyMember, mutation domain.Mutation) error {
+	return r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(r.residentTable).Create(&resident).Error; err != nil {
+			return err
+		}
+		if err := openResidencePeriod(tx, resident.ID, resident.RegionID, resident.BirthDate); err != nil {
+			return err
+		}
+		if err := tx.Table(r.memberTable).Omit("Resident").Create(&member).Error; err != nil {
+			return err
+		}
+		return tx.Table(r.table).Create(&mutation).Error
+	})
+}
+
+func (r *mutationRepository) RegisterDeath(c context.Context, mutation domain.Mutation, member *domain.FamilyMember, successor *domain.FamilyMember) error {
+	return r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table(r.residentTable).
+			Where("id = ? AND status = ?", mutation.ResidentID, domain.ResidentStatusActive).
+			Updates(map[string]interface{}{
+				"status":     domain.ResidentStatusDeceased,
+				"death_date": mutation.EventDate,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("resident is not active")
+		}
+
+		if err := closeResidencePeriods(tx, []uuid.UUID{mutation.ResidentID}, mutation.EventDate); err != nil {
+			return err
+		}
+		if member != nil {
+			if err := closeMemberships(tx, []uuid.UUID{member.ResidentID}, mutation.EventDate); err != nil {
+				return err
+			}
+		}
+		if successor != nil {
+			// Hubungan lama kepala keluarga pengganti diakhiri dan diganti
+			// keanggotaan baru sebagai kepala keluarga mulai tanggal kematian.
+			if err := closeMemberships(tx, []uuid.UUID{successor.ResidentID}, mutation.EventDate); err != nil {
+				return err
+			}
+			id, err := uuid.NewUUID()
+			if err != nil {
+				return err
+			}
+			head := domain.FamilyMember{
+				ID:           id,
+				FamilyID:     successor.FamilyID,
+				ResidentID:   successor.ResidentID,
+				Relationship: domain.RelationshipKepalaKeluarga,
+				ValidFrom:    mutation.EventDate,
+			}
+			if err := tx.Table(r.memberTable).Omit("Resident").Create(&head).Error; err != nil {
+				return err
+			}
+			if err := tx.Table(r.familyTable).Where(queryFindByID, successor.FamilyID).
+				Update("head_id", successor.ResidentID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Table(r.table).Create(&mutation).Error
+	})
+}
+
+func (r *mutationRepository) Retrieve(c context.Context, filter domain.Filter) (mutations []domain.Mutation, meta domain.MetaResponse, err error) {
+	asOf := func(db *gorm.DB) *gorm.DB {
+		if filter.AsOf.IsZero() {
+			return db
+		}
+		return db.Where("event_date <= ?", filter.AsOf)
+	}
+
+	meta, err = r.builder.retrieve(r.scoped(c), filter, &mutations, asOf)
+	if err != nil {
+		return nil, domain.MetaResponse{}, err
+	}
+	return mutations, meta, nil
+}
+
+func (r *mutationRepository) GetById(c context.Context, id uuid.UUID) (mutation domain.Mutation, err error) {
+	result := r.scoped(c).Where(queryFindByID, id).First(&mutation)
+	if result.Error != nil {
+		return domain.Mutation{}, result.Error
+	}
+	return mutation, nil
+}
+
+func (r *mutationRepository) RevertDeath(c context.Context, mutation domain.Mutation) error {
+	return r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		result := scopeRegion(c, tx.Table(r.table).Model(&domain.Mutation{}), r.table+".region_id").
+			Where(queryFindByID+" AND type = ?", mutation.ID, domain.MutationTypeDeath).
+			Delete(&domain.Mutation{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no mutation was deleted")
+		}
+
+		result = tx.Table(r.residentTable).
+			Where("id = ? AND status = ?", mutation.ResidentID, domain.ResidentStatusDeceased).
+			Updates(map[string]interface{}{
+				"status":     domain.ResidentStatusActive,
+				"death_date": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("resident is not recorded as deceased")
+		}
+
+		err := tx.Table(domain.ResidencePeriodTable).
+			Where("resident_id = ? AND valid_until = ?", mutation.ResidentID, mutation.EventDate).
+			Update("valid_until", nil).Error
+		if err != nil {
+			return err
+		}
+
+		var member domain.FamilyMember
+		err = tx.Table(r.memberTable).
+			Where("resident_id = ? AND valid_until = ?", mutation.ResidentID, mutation.EventDate).
+			Where(queryActiveFamily).
+			Order("valid_from DESC").
+			First(&member).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if member.Relationship == domain.RelationshipKepalaKeluarga {
+			if err := r.revertSuccession(tx, member, mutation.EventDate); err != nil {
+				return err
+			}
+		}
+		return tx.Table(r.memberTable).Where(queryFindByID, member.ID).Update("valid_until", nil).Error
+	})
+}
+
+// revertSuccession mengembalikan kepala keluarga pengganti yang ditetapkan
+// pada tanggal kematian ke hubungan sebelumnya dan menjadikan head kembali
+// kepala keluarga. Kematian tidak dapat dibatalkan bila kepala keluarga sudah
+// berganti lagi sesudahnya.
+func (r *mutationRepository) revertSuccession(tx *gorm.DB, head domain.FamilyMember, date time.Time) error {
+	var successor domain.FamilyMember
+	err := tx.Table(r.memberTable).
+		Where("family_id = ? AND relationship = ? AND "+queryCurrentMember, head.FamilyID, domain.RelationshipKepalaKeluarga).
+		First(&successor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !successor.ValidFrom.Equal(date) {
+		return errors.New("the family head has changed since the death, the death can not be reverted")
+	}
+
+	if err := tx.Table(r.memberTable).Where(queryFindByID, successor.ID).Delete(&domain.FamilyMember{}).Error; err != nil {
+		return err
+	}
+	err = tx.Table(r.memberTable).
+		Where("family_id = ? AND resident_id = ? AND valid_until = ?", head.FamilyID, successor.ResidentID, date).
+		Update("valid_until", nil).Error
+	if err != nil {
+		return err
+	}
+	return tx.Table(r.familyTable).Where(queryFindByID, head.FamilyID).Update("head_id", head.ResidentID).Error
+}
+
+func (r *mutationRepository) Restore(c context.Context, id uuid.UUID) error {
+	var mutation domain.Mutation
+	if err := r.scoped(c).Unscoped().Where(queryFindByID, id).First(&mutation).Error; err != nil {
+		return err
+	}
+	if mutation.Type == domain.MutationTypeDeath {
+		return errors.New("a deleted death can not be restored, register the death again")
+	}
+
+	result := r.scoped(c).Unscoped().Where(queryFindByID+" AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no deleted mutation was restored")
+	}
+	return nil
+}
+
+func (r *mutationRepository) Purge(c context.Context, before time.Time) (int64, error) {
+	result := r.database.WithContext(c).Unscoped().Table(r.table).Where("deleted_at < ?", before).Delete(&domain.Mutation{})
+	return result.RowsAffected, result.Error
+}
+
+// scoped membatasi query peristiwa pada wilayah yang dibebani peristiwa
+// tersebut di dalam cakupan wilayah pengguna.
+func (r *mutationRepository) scoped(c context.Context) *gorm.DB {
+	return scopeRegion(c, r.database.WithContext(c).Table(r.table).Model(&domain.Mutation{}), r.table+".region_id")
+}
@@ -0,0 +1,447 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// defaultLimit dipakai bila DEFAULT_PAGE_SIZE tidak diisi agar jumlah halaman
+// tidak pernah dihitung dengan pembagi nol.
+const defaultLimit = 10
+
+// Jenis pencocokan kolom pencarian.
+const (
+	searchContains = iota
+	searchPrefix
+	searchExact
+)
+
+// Jenis syarat kolom pada domain.Filter.Filters. Batas bawah dan atas range
+// bersifat inklusif dan salah satunya boleh kosong.
+const (
+	// filterEquals mencocokkan satu nilai, misalnya sex:male.
+	filterEquals = iota
+	// filterIn mencocokkan salah satu nilai yang dipisah koma, misalnya
+	// religion:hindu,islam.
+	filterIn
+	// filterRange membatasi nilai angka, misalnya created_at:1700000000,.
+	filterRange
+	// filterDateRange membatasi tanggal berformat 2006-01-02, misalnya
+	// birth_date:2000-01-01,2005-12-31.
+	filterDateRange
+	// filterRegion membatasi kolom region_id pada wilayah dengan kode
+	// tersebut beserta seluruh turunannya, misalnya region:51.06.01.2001.
+	filterRegion
+)
+
+// errInvalidCursor dikembalikan bila cursor rusak atau dibuat untuk urutan
+// yang berbeda.
+var errInvalidCursor = errors.New("invalid cursor, request the first page again")
+
+// schemaCache menyimpan hasil parsing schema model untuk membaca dan
+// mengisi nilai cursor.
+var schemaCache = &sync.Map{}
+
+// searchColumn adalah kolom yang dicari oleh domain.Filter.Search.
+type searchColumn struct {
+	column string
+	match  int
+}
+
+// fieldFilter adalah kolom yang boleh disaring beserta jenis syaratnya.
+type fieldFilter struct {
+	column string
+	kind   int
+}
+
+// queryCondition adalah syarat tambahan milik repository, misalnya AsOf, yang
+// dihitung sebagai bagian dari FilteredRecords.
+type queryCondition func(db *gorm.DB) *gorm.DB
+
+// orderColumn adalah satu kolom ORDER BY.
+type orderColumn struct {
+	column string
+	desc   bool
+}
+
+// cursor adalah isi cursor sebelum dikodekan: urutan saat cursor dibuat, arah
+// halaman dan nilai kolom urutan pada baris acuan.
+type cursor struct {
+	SortBy  string            `json:"s"`
+	OrderBy string            `json:"o"`
+	Prev    bool              `json:"p,omitempty"`
+	Values  []json.RawMessage `json:"v"`
+}
+
+// queryBuilder menyusun query daftar data yang sama untuk seluruh repository:
+// pencarian pada kolom yang ditentukan, syarat kolom bertipe, urutan dari
+// kolom yang diizinkan saja, pagination halaman atau cursor dan metadatanya.
+// Nama kolom hanya berasal dari konfigurasi repository sehingga tidak pernah
+// dibentuk dari input pengguna.
+type queryBuilder struct {
+	search  []searchColumn
+	filters map[string]fieldFilter
+	// sorts memetakan nilai sort_by ke nama kolomnya. Kolom harus NOT NULL
+	// agar dapat dipakai sebagai cursor dan, bila tidak unik, memiliki index
+	// gabungan (kolom, id) agar syarat keyset langsung mencari pada index.
+	sorts        map[string]string
+	defaultSort  string
+	defaultOrder string
+	// tiebreaker adalah kolom yang diurutkan naik di belakang kolom sort_by.
+	// Gabungan kolomnya harus unik agar urutan data sama di setiap halaman
+	// dan cursor selalu menunjuk satu baris.
+	tiebreaker []string
+	preloads   []string
+	// preloadDeleted ikut memuat relasi yang sudah dihapus, misalnya penduduk
+	// yang sudah digabung pada riwayat data ganda.
+	preloadDeleted bool
+	pageInit       int64
+	limitInit      int64
+}
+
+// retrieve membaca data base ke dest yang berupa pointer ke slice model.
+// TotalRecords dihitung dari base, sedangkan FilteredRecords sesudah
+// pencarian, syarat kolom dan conditions. base tidak diubah sehingga boleh
+// berupa query yang sudah dibatasi cakupan wilayah.
+//
+// Bila filter berisi Cursor atau WithCursor, data dibaca dengan keyset
+// pagination: baris sesudah (atau sebelum) baris acuan cursor dipilih melalui
+// kolom urutan sehingga halaman yang jauh sama cepatnya dengan halaman
+// pertama. Page diabaikan dan meta berisi NextCursor serta PrevCursor.
+// TotalRecords, FilteredRecords dan TotalPages hanya dihitung pada halaman
+// pertama tanpa Cursor karena dua COUNT(*) di setiap halaman justru
+// menghilangkan keuntungan keyset pagination pada data yang sangat banyak.
+func (b queryBuilder) retrieve(base *gorm.DB, filter domain.Filter, dest interface{}, conditions ...queryCondition) (meta domain.MetaResponse, err error) {
+	if filter.ShowDeleted {
+		base = base.Unscoped()
+	}
+	sortBy, orderBy, columns, err := b.order(filter)
+	if err != nil {
+		return domain.MetaResponse{}, err
+	}
+
+	query := base.Session(&gorm.Session{})
+	query = b.where(query, filter.Search)
+	for _, expression := range filter.Filters {
+		if query, err = b.filter(query, expression); err != nil {
+			return domain.MetaResponse{}, err
+		}
+	}
+	for _, condition := range conditions {
+		query = condition(query)
+	}
+
+	if filter.Cursor == "" {
+		if err = base.Session(&gorm.Session{}).Count(&meta.TotalRecords).Error; err != nil {
+			return domain.MetaResponse{}, err
+		}
+		if err = query.Session(&gorm.Session{}).Count(&meta.FilteredRecords).Error; err != nil {
+			return domain.MetaResponse{}, err
+		}
+	}
+	for _, preload := range b.preloads {
+		if b.preloadDeleted {
+			query = query.Preload(preload, func(db *gorm.DB) *gorm.DB { return db.Unscoped() })
+			continue
+		}
+		query = query.Preload(preload)
+	}
+
+	if filter.Cursor != "" || filter.WithCursor {
+		return b.retrieveCursor(query, filter, sortBy, orderBy, columns, dest, meta)
+	}
+
+	meta.Page, meta.PerPage = 1, meta.FilteredRecords
+	if filter.WithPagination {
+		meta.Page, meta.PerPage = b.pagination(filter)
+		query = query.Offset(int((meta.Page - 1) * meta.PerPage)).Limit(int(meta.PerPage))
+	}
+	if meta.PerPage > 0 {
+		meta.TotalPages = (meta.FilteredRecords + meta.PerPage - 1) / meta.PerPage
+	}
+
+	if err = query.Order(orderClause(columns, false)).Find(dest).Error; err != nil {
+		return domain.MetaResponse{}, err
+	}
+	return meta, nil
+}
+
+// retrieveCursor membaca satu halaman sesudah atau sebelum baris acuan
+// cursor. Satu baris lebih dibaca untuk mengetahui apakah masih ada halaman
+// berikutnya pada arah tersebut.
+func (b queryBuilder) retrieveCursor(query *gorm.DB, filter domain.Filter, sortBy string, orderBy string, columns []orderColumn, dest interface{}, meta domain.MetaResponse) (domain.MetaResponse, error) {
+	model, err := schema.Parse(dest, schemaCache, query.NamingStrategy)
+	if err != nil {
+		return domain.MetaResponse{}, err
+	}
+	fields := make([]*schema.Field, 0, len(columns))
+	for _, column := range columns {
+		field := model.LookUpField(column.column)
+		if field == nil {
+			return domain.MetaResponse{}, fmt.Errorf("column %s can not be used as cursor", column.column)
+		}
+		fields = append(fields, field)
+	}
+
+	_, meta.PerPage = b.pagination(filter)
+	if meta.PerPage > 0 {
+		meta.TotalPages = (meta.FilteredRecords + meta.PerPage - 1) / meta.PerPage
+	}
+
+	prev := false
+	if filter.Cursor != "" {
+		current, err := decodeCursor(filter.Cursor)
+		if err != nil || current.SortBy != sortBy || current.OrderBy != orderBy || len(current.Values) != len(fields) {
+			return domain.MetaResponse{}, errInvalidCursor
+		}
+		values := make([]interface{}, 0, len(fields))
+		for i, field := range fields {
+			value := reflect.New(field.FieldType)
+			if err := json.Unmarshal(current.Values[i], value.Interface()); err != nil {
+				return domain.MetaResponse{}, errInvalidCursor
+			}
+			values = append(values, value.Elem().Interface())
+		}
+		prev = current.Prev
+		condition, args := keysetCondition(columns, values, prev)
+		query = query.Where(condition, args...)
+	}
+
+	if err := query.Order(orderClause(columns, prev)).Limit(int(meta.PerPage + 1)).Find(dest).Error; err != nil {
+		return domain.MetaResponse{}, err
+	}
+
+	rows := reflect.ValueOf(dest).Elem()
+	more := int64(rows.Len()) > meta.PerPage
+	if more {
+		rows.Set(rows.Slice(0, int(meta.PerPage)))
+	}
+	if prev {
+		swap := reflect.Swapper(rows.Interface())
+		for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+	if rows.Len() == 0 {
+		return meta, nil
+	}
+
+	if more || prev {
+		if meta.NextCursor, err = encodeCursor(query.Statement.Context, sortBy, orderBy, false, fields, rows.Index(rows.Len()-1)); err != nil {
+			return domain.MetaResponse{}, err
+		}
+	}
+	if (more && prev) || (!prev && filter.Cursor != "") {
+		if meta.PrevCursor, err = encodeCursor(query.Statement.Context, sortBy, orderBy, true, fields, rows.Index(0)); err != nil {
+			return domain.MetaResponse{}, err
+		}
+	}
+	return meta, nil
+}
+
+func (b queryBuilder) pagination(filter domain.Filter) (page int64, limit int64) {
+	page, limit = filter.Page, filter.Limit
+	if page <= 0 {
+		page = b.pageInit
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = b.limitInit
+	}
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	return page, limit
+}
+
+// order menentukan kolom urutan dari sort_by dan order_by (asc atau desc)
+// beserta tiebreaker-nya.
+func (b queryBuilder) order(filter domain.Filter) (sortBy string, orderBy string, columns []orderColumn, err error) {
+	sortBy, orderBy = filter.SortBy, strings.ToLower(filter.OrderBy)
+	if sortBy == "" {
+		sortBy = b.defaultSort
+		if orderBy == "" {
+			orderBy = b.defaultOrder
+		}
+	}
+	column, ok := b.sorts[sortBy]
+	if !ok {
+		return "", "", nil, fmt.Errorf("unknown sort_by %q", sortBy)
+	}
+	switch orderBy {
+	case "":
+		orderBy = "asc"
+	case "asc", "desc":
+	default:
+		return "", "", nil, fmt.Errorf("unknown order_by %q, use asc or desc", filter.OrderBy)
+	}
+
+	columns = append(columns, orderColumn{column: column, desc: orderBy == "desc"})
+	for _, tiebreaker := range b.tiebreaker {
+		if tiebreaker != column {
+			columns = append(columns, orderColumn{column: tiebreaker})
+		}
+	}
+	return sortBy, orderBy, columns, nil
+}
+
+func (b queryBuilder) where(query *gorm.DB, search string) *gorm.DB {
+	if search == "" || len(b.search) == 0 {
+		return query
+	}
+	conditions := make([]string, 0, len(b.search))
+	args := make([]interface{}, 0, len(b.search))
+	for _, column := range b.search {
+		switch column.match {
+		case searchPrefix:
+			conditions = append(conditions, column.column+" LIKE ?")
+			args = append(args, search+"%")
+		case searchExact:
+			conditions = append(conditions, column.column+" = ?")
+			args = append(args, search)
+		default:
+			conditions = append(conditions, column.column+" LIKE ?")
+			args = append(args, "%"+search+"%")
+		}
+	}
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// filter memasang satu syarat kolom berformat kolom:nilai.
+func (b queryBuilder) filter(query *gorm.DB, expression string) (*gorm.DB, error) {
+	name, value, ok := strings.Cut(expression, ":")
+	if !ok || value == "" {
+		return nil, fmt.Errorf("invalid filter %q, use field:value", expression)
+	}
+	field, ok := b.filters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown filter field %q", name)
+	}
+
+	switch field.kind {
+	case filterIn:
+		values := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("invalid filter %q, use field:value", expression)
+		}
+		return query.Where(field.column+" IN ?", values), nil
+	case filterRange:
+		return rangeFilter(query, field.column, expression, value, func(bound string) (interface{}, error) {
+			return strconv.ParseFloat(bound, 64)
+		})
+	case filterDateRange:
+		return rangeFilter(query, field.column, expression, value, func(bound string) (interface{}, error) {
+			return time.Parse("2006-01-02", bound)
+		})
+	case filterRegion:
+		condition, args := regionCodeCondition("code", []string{value})
+		return query.Where(field.column+" IN (SELECT id FROM "+domain.RegionTable+" WHERE "+condition+")", args...), nil
+	}
+	return query.Where(field.column+" = ?", value), nil
+}
+
+// rangeFilter memasang batas bawah dan atas berformat min,max.
+func rangeFilter(query *gorm.DB, column string, expression string, value string, parse func(string) (interface{}, error)) (*gorm.DB, error) {
+	min, max, ok := strings.Cut(value, ",")
+	min, max = strings.TrimSpace(min), strings.TrimSpace(max)
+	if !ok || (min == "" && max == "") {
+		return nil, fmt.Errorf("invalid filter %q, use field:min,max", expression)
+	}
+	if min != "" {
+		bound, err := parse(min)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", expression, err)
+		}
+		query = query.Where(column+" >= ?", bound)
+	}
+	if max != "" {
+		bound, err := parse(max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", expression, err)
+		}
+		query = query.Where(column+" <= ?", bound)
+	}
+	return query, nil
+}
+
+// orderClause menyusun ORDER BY, reverse membalik seluruh arah untuk membaca
+// halaman sebelumnya.
+func orderClause(columns []orderColumn, reverse bool) string {
+	parts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		direction := "ASC"
+		if column.desc != reverse {
+			direction = "DESC"
+		}
+		parts = append(parts, column.column+" "+direction)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keysetCondition memilih baris sesudah baris acuan menurut urutan columns,
+// atau sebelumnya bila prev, misalnya untuk name ASC, id ASC:
+// (name > ?) OR (name = ? AND id > ?).
+func keysetCondition(columns []orderColumn, values []interface{}, prev bool) (string, []interface{}) {
+	conditions := make([]string, 0, len(columns))
+	args := []interface{}{}
+	for i, column := range columns {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, columns[j].column+" = ?")
+			args = append(args, values[j])
+		}
+		operator := ">"
+		if column.desc != prev {
+			operator = "<"
+		}
+		parts = append(parts, column.column+" "+operator+" ?")
+		args = append(args, values[i])
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+func encodeCursor(c context.Context, sortBy string, orderBy string, prev bool, fields []*schema.Field, row reflect.Value) (string, error) {
+	current := cursor{SortBy: sortBy, OrderBy: orderBy, Prev: prev, Values: make([]json.RawMessage, 0, len(fields))}
+	for _, field := range fields {
+		value, _ := field.ValueOf(c, row)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		current.Values = append(current.Values, raw)
+	}
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(value string) (current cursor, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor{}, err
+	}
+	err = json.Unmarshal(raw, &current)
+	return current, err
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+type recapRepository struct {
+	database      *gorm.DB
+	residentTable string
+	memberTable   string
+	familyTable   string
+	periodTable   string
+	regionTable   string
+	mutationTable string
+}
+
+func NewRecapRepository(db *gorm.DB) domain.RecapRepository {
+	return &recapRepository{
+		database:      db,
+		residentTable: domain.ResidentTable,
+		memberTable:   domain.FamilyMemberTable,
+		familyTable:   domain.FamilyTable,
+		periodTable:   domain.ResidencePeriodTable,
+		regionTable:   domain.RegionTable,
+		mutationTable: domain.MutationTable,
+	}
+}
+
+func (r *recapRepository) CountResidents(c context.Context, query domain.RecapQuery, dimension string) (rows []domain.RecapRow, err error) {
+	expression, args, err := r.dimensionExpression(query, dimension)
+	if err != nil {
+		return nil, err
+	}
+
+	db := r.database.WithContext(c).
+		Table(r.residentTable+" AS p").
+		Joins("JOIN "+r.periodTable+" AS rp ON rp.resident_id = p.id AND "+validAt("rp"), query.AsOf, query.AsOf).
+		Joins("JOIN " + r.regionTable + " AS g ON g.id = rp.region_id").
+		Where("p.deleted_at IS NULL")
+	db = r.whereRegion(c, db, query)
+
+	result := db.Select("CAST("+expression+" AS CHAR) AS `key`, p.sex AS sex, COUNT(*) AS total", args...).
+		Group("`key`, p.sex").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rows, nil
+}
+
+func (r *recapRepository) CountHouseholds(c context.Context, query domain.RecapQuery) (rows []domain.RecapRow, err error) {
+	db := r.database.WithContext(c).
+		Table(r.memberTable+" AS m").
+		Joins("JOIN "+r.familyTable+" AS f ON f.id = m.family_id AND f.deleted_at IS NULL").
+		Joins("JOIN "+r.residentTable+" AS p ON p.id = m.resident_id AND p.deleted_at IS NULL").
+		Joins("JOIN "+r.periodTable+" AS rp ON rp.resident_id = m.resident_id AND "+validAt("rp"), query.AsOf, query.AsOf).
+		Joins("JOIN "+r.regionTable+" AS g ON g.id = rp.region_id").
+		Where("m.relationship = ?", domain.RelationshipKepalaKeluarga).
+		Where(validAt("m"), query.AsOf, query.AsOf)
+	db = r.whereRegion(c, db, query)
+
+	result := db.Select(fmt.Sprintf("LEFT(g.code, %d) AS `key`, COUNT(DISTINCT m.family_id) AS total", query.GroupCodeLength)).
+		Group("`key`").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rows, nil
+}
+
+// CountMutations menghitung peristiwa kependudukan per wilayah dan jenis
+// peristiwa dengan tanggal peristiwa di antara from dan to (inklusif).
+// Peristiwa penduduk yang dihapus tidak dihitung, sama seperti penduduknya
+// pada CountResidents, agar neraca mutasi bulanan tetap seimbang.
+func (r *recapRepository) CountMutations(c context.Context, query domain.RecapQuery, from time.Time, to time.Time) (rows []domain.MutationRow, err error) {
+	db := r.database.WithContext(c).
+		Table(r.mutationTable+" AS m").
+		Joins("JOIN "+r.residentTable+" AS p ON p.id = m.resident_id AND p.deleted_at IS NULL").
+		Joins("JOIN "+r.regionTable+" AS g ON g.id = m.region_id").
+		Where("m.event_date BETWEEN ? AND ? AND m.deleted_at IS NULL", from, to)
+	db = r.whereRegion(c, db, query)
+
+	result := db.Select(fmt.Sprintf("LEFT(g.code, %d) AS `key`, m.type AS type, COUNT(*) AS total", query.GroupCodeLength)).
+		Group("`key`, m.type").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rows, nil
+}
+
+// whereRegion membatasi data pada wilayah terpilih beserta seluruh turunannya
+// memanfaatkan prefix kode wilayah, lalu pada cakupan wilayah pengguna.
+func (r *recapRepository) whereRegion(c context.Context, db *gorm.DB, query domain.RecapQuery) *gorm.DB {
+	if query.RegionCode != "" {
+		db = db.Where("(g.code = ? OR g.code LIKE ?)", query.RegionCode, query.RegionCode+".%")
+	}
+	scope := domain.RegionScopeFromContext(c)
+	if !scope.Restricted {
+		return db
+	}
+	if len(scope.Codes) == 0 {
+		return db.Where("1 = 0")
+	}
+	condition, args := regionCodeCondition("g.code", scope.Codes)
+	return db.Where(condition, args...)
+}
+
+// dimensionExpression hanya menerima dimensi yang dikenal sehingga ekspresi
+// SQL tidak pernah dibentuk dari input pengguna.
+func (r *recapRepository) dimensionExpression(query domain.RecapQuery, dimension string) (string, []interface{}, error) {
+	switch dimension {
+	case domain.RecapDimensionRegion:
+		return fmt.Sprintf("LEFT(g.code, %d)", query.GroupCodeLength), nil, nil
+	case domain.RecapDimensionAgeGroup:
+		expression := fmt.Sprintf("LEAST(TIMESTAMPDIFF(YEAR, p.birth_date, ?) DIV %d, %d)", domain.AgeGroupSize, domain.AgeGroupCount-1)
+		return expression, []interface{}{query.AsOf}, nil
+	case domain.RecapDimensionReligion:
+		return "p.religion", nil, nil
+	case domain.RecapDimensionEducation:
+		return "p.education", nil, nil
+	case domain.RecapDimensionOccupation:
+		return "p.occupation", nil, nil
+	case domain.RecapDimensionMaritalStatus:
+		return "p.marital_status", nil, nil
+	}
+	return "", nil, errors.New("unknown recap dimension")
+}
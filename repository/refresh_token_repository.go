@@ -38,6 +38,14 @@ func (r *refreshTokenRepository) Create(c context.Context, refreshToken domain.R
 	return nil
 }
 
+func (r *refreshTokenRepository) GetByToken(c context.Context, token string) (refreshToken domain.RefreshToken, err error) {
+	result := r.database.WithContext(c).Table(r.table).Where("token = ?", token).First(&refreshToken)
+	if result.Error != nil {
+		return domain.RefreshToken{}, result.Error
+	}
+	return refreshToken, nil
+}
+
 func (r *refreshTokenRepository) Revoke(c context.Context, token string) error {
 	result := r.database.WithContext(c).Table(r.table).Where("token = ?", token).Update("revoked", true)
 	if result.Error != nil {
@@ -49,8 +57,10 @@ func (r *refreshTokenRepository) Revoke(c context.Context, token string) error {
 	return nil
 }
 
-func (r *refreshTokenRepository) RevokeByPairToken(c context.Context, token string) error {
-	result := r.database.WithContext(c).Table(r.table).Where("pair_token = ?", token).Update("revoked", true)
+func (r *refreshTokenRepository) Rotate(c context.Context, token string) error {
+	result := r.database.WithContext(c).Table(r.table).
+		Where("token = ? AND revoked = ?", token, false).
+		Updates(map[string]interface{}{"revoked": true, "rotated_at": time.Now().Unix()})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -60,8 +70,12 @@ func (r *refreshTokenRepository) RevokeByPairToken(c context.Context, token stri
 	return nil
 }
 
-func (r *refreshTokenRepository) RevokeByUserID(c context.Context, userID uuid.UUID) error {
-	result := r.database.WithContext(c).Table(r.table).Where("user_id = ?", userID).Update("revoked", true)
+func (r *refreshTokenRepository) RevokeBySessionID(c context.Context, sessionID uuid.UUID) error {
+	return r.database.WithContext(c).Table(r.table).Where("session_id = ?", sessionID).Update("revoked", true).Error
+}
+
+func (r *refreshTokenRepository) RevokeByPairTokenID(c context.Context, pairTokenID string) error {
+	result := r.database.WithContext(c).Table(r.table).Where("pair_token_id = ?", pairTokenID).Update("revoked", true)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -71,6 +85,15 @@ func (r *refreshTokenRepository) RevokeByUserID(c context.Context, userID uuid.U
 	return nil
 }
 
+// RevokeByUserID tidak menganggap pengguna tanpa token sebagai kesalahan.
+func (r *refreshTokenRepository) RevokeByUserID(c context.Context, userID uuid.UUID) error {
+	result := r.database.WithContext(c).Table(r.table).Where("user_id = ?", userID).Update("revoked", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 func (r *refreshTokenRepository) IsValid(c context.Context, token string) bool {
 	var refreshToken domain.RefreshToken
 	result := r.database.WithContext(c).Table(r.table).Where("token = ? AND revoked = ?", token, false).First(&refreshToken)
@@ -91,8 +114,10 @@ func (r *refreshTokenRepository) Delete(c context.Context, token string) error {
 	return nil
 }
 
+// DeleteExpiredToken hanya menghapus token kedaluwarsa. Token yang sudah
+// dirotasi tetap disimpan sampai kedaluwarsa agar pemakaian ulang terdeteksi.
 func (r *refreshTokenRepository) DeleteExpiredToken(c context.Context, millisDateTime int64) error {
-	result := r.database.WithContext(c).Table(r.table).Where("expires_at < ?", millisDateTime).Or("revoked = ?", 1).Delete(&domain.RefreshToken{})
+	result := r.database.WithContext(c).Table(r.table).Where("expires_at < ?", millisDateTime).Delete(&domain.RefreshToken{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type regionRepository struct {
+	database  *gorm.DB
+	table     string
+	pageInit  int64
+	limitInit int64
+	builder   queryBuilder
+}
+
+func NewRegionRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.RegionRepository {
+	return &regionRepository{
+		database:  db,
+		table:     table,
+		pageInit:  pageInit,
+		limitInit: limitInit,
+		builder: queryBuilder{
+			search: []searchColumn{{"name", searchContains}, {"code", searchPrefix}},
+			filters: map[string]fieldFilter{
+				"level":     {"level", filterIn},
+				"type":      {"type", filterIn},
+				"parent_id": {"parent_id", filterEquals},
+			},
+			sorts: map[string]string{
+				"code": "code",
+				"name": "name",
+			},
+			defaultSort: "code",
+			tiebreaker:  []string{"id"},
+			pageInit:    pageInit,
+			limitInit:   limitInit,
+		},
+	}
+}
+
+func (r *regionRepository) Create(c context.Context, data domain.Region) error {
+	result := r.database.WithContext(c).Table(r.table).Create(&data)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *regionRepository) Retrieve(c context.Context, filter domain.Filter) (regions []domain.Region, meta domain.MetaResponse, err error) {
+	meta, err = r.builder.retrieve(r.database.WithContext(c).Table(r.table).Model(&domain.Region{}), filter, &regions)
+	if err != nil {
+		return nil, domain.MetaResponse{}, err
+	}
+	return regions, meta, nil
+}
+
+func (r *regionRepository) GetById(c context.Context, id uuid.UUID) (region domain.Region, err error) {
+	result := r.database.WithContext(c).Table(r.table).Where(queryFindByID, id).First(&region)
+	if result.Error != nil {
+		return domain.Region{}, result.Error
+	}
+	return region, nil
+}
+
+func (r *regionRepository) GetByCode(c context.Context, code string) (region domain.Region, err error) {
+	result := r.database.WithContext(c).Table(r.table).Where("code = ?", code).First(&region)
+	if result.Error != nil {
+		return domain.Region{}, result.Error
+	}
+	return region, nil
+}
+
+func (r *regionRepository) GetByCodes(c context.Context, codes []string) (regions []domain.Region, err error) {
+	if len(codes) == 0 {
+		return []domain.Region{}, nil
+	}
+	result := r.database.WithContext(c).Table(r.table).Where("code IN ?", codes).Order("code ASC").Find(&regions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return regions, nil
+}
+
+func (r *regionRepository) GetChildren(c context.Context, parentID uuid.UUID) (regions []domain.Region, err error) {
+	result := r.database.WithContext(c).Table(r.table).Where("parent_id = ?", parentID).Order("code ASC").Find(&regions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return regions, nil
+}
+
+// Update menolak perubahan kode wilayah yang masih memiliki wilayah turunan
+// karena kode turunan harus tetap diawali kode induknya.
+func (r *regionRepository) Update(c context.Context, id uuid.UUID, data domain.Region) (region domain.Region, err error) {
+	err = r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if data.Code != "" {
+			var current domain.Region
+			if err := tx.Table(r.table).Clauses(clause.Locking{Strength: "UPDATE"}).Where(queryFindByID, id).First(&current).Error; err != nil {
+				return err
+			}
+			if current.Code != data.Code {
+				var children int64
+				if err := tx.Table(r.table).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+					return err
+				}
+				if children > 0 {
+					return errors.New("region code can not be changed while the region still has child regions")
+				}
+			}
+		}
+
+		result := tx.Table(r.table).Where(queryFindByID, id).Updates(data)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no region was updated")
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Region{}, err
+	}
+	err = r.database.WithContext(c).Table(r.table).Where(queryFindByID, id).First(&region).Error
+	if err != nil {
+		return domain.Region{}, err
+	}
+	return region, nil
+}
+
+func (r *regionRepository) Delete(c context.Context, id uuid.UUID) error {
+	var children int64
+	if err := r.database.WithContext(c).Table(r.table).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("region still has child regions")
+	}
+
+	result := r.database.WithContext(c).Table(r.table).Where(queryFindByID, id).Delete(&domain.Region{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no region was deleted")
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+const (
+	preloadDuplicateResident  = "Resident"
+	preloadDuplicateDuplicate = "Duplicate"
+	queryPendingDuplicate     = "status = '" + domain.ResidentDuplicateStatusPending + "'"
+)
+
+type residentDuplicateRepository struct {
+	database  *gorm.DB
+	table     string
+	pageInit  int64
+	limitInit int64
+	builder   queryBuilder
+}
+
+func NewResidentDuplicateRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.ResidentDuplicateRepository {
+	return &residentDuplicateRepository{
+		database:  db,
+		table:     table,
+		pageInit:  pageInit,
+		limitInit: limitInit,
+		builder: queryBuilder{
+			filters: map[string]fieldFilter{
+				"status":      {"status", filterIn},
+				"resident_id": {"resident_id", filterEquals},
+				"score":       {"score", filterRange},
+			},
+			sorts: map[string]string{
+				"score":      "score",
+				"created_at": "created_at",
+				"updated_at": "updated_at",
+			},
+			defaultSort:    "score",
+			defaultOrder:   "desc",
+			tiebreaker:     []string{"id"},
+			preloads:       []string{preloadDuplicateResident, preloadDuplicateDuplicate},
+			preloadDeleted: true,
+			pageInit:       pageInit,
+			limitInit:      limitInit,
+		},
+	}
+}
+
+func (r *residentDuplicateRepository) Retrieve(c context.Context, filter domain.Filter) (duplicates []domain.ResidentDuplicate, meta domain.MetaResponse, err error) {
+	meta, err = r.builder.retrieve(r.database.WithContext(c).Table(r.table).Model(&domain.ResidentDuplicate{}), filter, &duplicates)
+	if err != nil {
+		return nil, domain.MetaResponse{}, err
+	}
+	return duplicates, meta, nil
+}
+
+func (r *residentDuplicateRepository) GetById(c context.Context, id uuid.UUID) (duplicate domain.ResidentDuplicate, err error) {
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	result := r.database.WithContext(c).Table(r.table).
+		Preload(preloadDuplicateResident, unscoped).
+		Preload(preloadDuplicateDuplicate, unscoped).
+		Where(queryFindByID, id).
+		First(&duplicate)
+	if result.Error != nil {
+		return domain.ResidentDuplicate{}, result.Error
+	}
+	return duplicate, nil
+}
+
+func (r *residentDuplicateRepository) Candidates(c context.Context) (residents []domain.Resident, err error) {
+	result := r.database.WithContext(c).Table(domain.ResidentTable).Model(&domain.Resident{}).
+		Select("id, nik, name, birth_date, sex, father_name, mother_name").
+		Find(&residents)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return residents, nil
+}
+
+func (r *residentDuplicateRepository) Record(c context.Context, duplicate domain.ResidentDuplicate) error {
+	var existing domain.ResidentDuplicate
+	err := r.database.WithContext(c).Table(r.table).
+		Where("resident_id = ? AND duplicate_id = ?", duplicate.ResidentID, duplicate.DuplicateID).
+		First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.database.WithContext(c).Table(r.table).Create(&duplicate).Error
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Status != domain.ResidentDuplicateStatusPending {
+		return nil
+	}
+	return r.database.WithContext(c).Table(r.table).
+		Where(queryFindByID, existing.ID).
+		Updates(map[string]interface{}{"score": duplicate.Score, "reasons": duplicate.Reasons}).Error
+}
+
+func (r *residentDuplicateRepository) Merge(c context.Context, duplicate domain.ResidentDuplicate, date time.Time) error {
+	keepID, removeID := *duplicate.KeptID, duplicate.RemovedID()
+
+	return r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if err := r.review(tx, duplicate); err != nil {
+			return err
+		}
+
+		var keepMember, removeMember domain.FamilyMember
+		keepFound, err := currentMember(tx, keepID, &keepMember)
+		if err != nil {
+			return err
+		}
+		removeFound, err := currentMember(tx, removeID, &removeMember)
+		if err != nil {
+			return err
+		}
+		if keepFound && removeFound {
+			if keepMember.FamilyID != removeMember.FamilyID {
+				return domain.ErrDuplicateFamilyConflict
+			}
+			if err := tx.Table(domain.FamilyMemberTable).Where(queryFindByID, removeMember.ID).Update("valid_until", date).Error; err != nil {
+				return err
+			}
+			if removeMember.Relationship == domain.RelationshipKepalaKeluarga {
+				if err := tx.Table(domain.FamilyMemberTable).Where(queryFindByID, keepMember.ID).Update("relationship", domain.RelationshipKepalaKeluarga).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Table(domain.FamilyMemberTable).Where("resident_id = ?", removeID).Update("resident_id", keepID).Error; err != nil {
+			return err
+		}
+		if err := tx.Table(domain.FamilyTable).Where("head_id = ?", removeID).Update("head_id", keepID).Error; err != nil {
+			return err
+		}
+		if err := tx.Table(domain.MutationTable).Where("resident_id = ?", removeID).Update("resident_id", keepID).Error; err != nil {
+			return err
+		}
+		if err := movePeriods(tx, keepID, removeID); err != nil {
+			return err
+		}
+		result := tx.Table(domain.ResidentTable).Where(queryFindByID, removeID).Delete(&domain.Resident{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("the resident to merge was already deleted")
+		}
+
+		return tx.Table(r.table).
+			Where("id <> ? AND "+queryPendingDuplicate+" AND (resident_id = ? OR duplicate_id = ?)", duplicate.ID, removeID, removeID).
+			Delete(&domain.ResidentDuplicate{}).Error
+	})
+}
+
+func (r *residentDuplicateRepository) Dismiss(c context.Context, duplicate domain.ResidentDuplicate) error {
+	return r.review(r.database.WithContext(c), duplicate)
+}
+
+// review menyimpan hasil tinjauan pada pasangan yang masih menunggu sehingga
+// pasangan yang sama tidak dapat ditinjau dua kali.
+func (r *residentDuplicateRepository) review(db *gorm.DB, duplicate domain.ResidentDuplicate) error {
+	result := db.Table(r.table).
+		Where(queryFindByID+" AND "+queryPendingDuplicate, duplicate.ID).
+		Updates(map[string]interface{}{
+			"status":      duplicate.Status,
+			"kept_id":     duplicate.KeptID,
+			"reviewed_by": duplicate.ReviewedBy,
+			"reviewed_at": duplicate.ReviewedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("the duplicate was already reviewed")
+	}
+	return nil
+}
+
+// movePeriods memindahkan masa tinggal penduduk removeID ke keepID. Bagian
+// yang tumpang tindih dengan masa tinggal keepID dibuang agar orang yang sama
+// tidak dihitung dua kali, sisanya tetap tercatat sebagai riwayat tinggal.
+func movePeriods(tx *gorm.DB, keepID uuid.UUID, removeID uuid.UUID) error {
+	var kept, moved []domain.ResidencePeriod
+	if err := tx.Table(domain.ResidencePeriodTable).Where("resident_id = ?", keepID).Find(&kept).Error; err != nil {
+		return err
+	}
+	if err := tx.Table(domain.ResidencePeriodTable).Where("resident_id = ?", removeID).Find(&moved).Error; err != nil {
+		return err
+	}
+
+	for _, period := range moved {
+		pieces := []domain.ResidencePeriod{period}
+		for _, keep := range kept {
+			pieces = subtractPeriod(pieces, keep)
+		}
+		if len(pieces) == 0 {
+			if err := tx.Table(domain.ResidencePeriodTable).Where(queryFindByID, period.ID).Delete(&domain.ResidencePeriod{}).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := tx.Table(domain.ResidencePeriodTable).Where(queryFindByID, period.ID).Updates(map[string]interface{}{
+			"resident_id": keepID,
+			"valid_from":  pieces[0].ValidFrom,
+			"valid_until": pieces[0].ValidUntil,
+		}).Error
+		if err != nil {
+			return err
+		}
+		for _, piece := range pieces[1:] {
+			id, err := uuid.NewUUID()
+			if err != nil {
+				return err
+			}
+			piece.ID = id
+			piece.ResidentID = keepID
+			if err := tx.Table(domain.ResidencePeriodTable).Create(&piece).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// subtractPeriod membuang rentang keep dari setiap masa tinggal. Masa tinggal
+// berlaku dari valid_from sampai sebelum valid_until, valid_until kosong
+// berarti masih berlaku.
+func subtractPeriod(periods []domain.ResidencePeriod, keep domain.ResidencePeriod) []domain.ResidencePeriod {
+	result := make([]domain.ResidencePeriod, 0, len(periods))
+	for _, period := range periods {
+		endsAfterKeepStarts := period.ValidUntil == nil || period.ValidUntil.After(keep.ValidFrom)
+		startsBeforeKeepEnds := keep.ValidUntil == nil || period.ValidFrom.Before(*keep.ValidUntil)
+		if !endsAfterKeepStarts || !startsBeforeKeepEnds {
+			result = append(result, period)
+			continue
+		}
+		if period.ValidFrom.Before(keep.ValidFrom) {
+			before := period
+			until := keep.ValidFrom
+			before.ValidUntil = &until
+			result = append(result, before)
+		}
+		if keep.ValidUntil != nil && (period.ValidUntil == nil || period.ValidUntil.After(*keep.ValidUntil)) {
+			after := period
+			after.ValidFrom = *keep.ValidUntil
+			result = append(result, after)
+		}
+	}
+	return result
+}
+
+// currentMember membaca keanggotaan KK penduduk yang masih berlaku ke member.
+func currentMember(tx *gorm.DB, residentID uuid.UUID, member *domain.FamilyMember) (bool, error) {
+	err := tx.Table(domain.FamilyMemberTable).
+		Where("resident_id = ? AND "+queryCurrentMember, residentID).
+		Where(queryActiveFamily).
+		First(member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
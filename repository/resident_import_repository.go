@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+// residentImportColumns adalah kolom biodata yang diperbarui bila NIK sudah
+// terdaftar. Status, tanggal meninggal dan tanggal pindah tidak pernah
+// diubah melalui impor.
+var residentImportColumns = []string{
+	"name", "birth_place", "birth_date", "sex", "religion", "marital_status", "education",
+	"occupation", "blood_type", "father_name", "mother_name", "region_id", "updated_at",
+}
+
+type residentImportRepository struct {
+	database      *gorm.DB
+	residentTable string
+	familyTable   string
+	memberTable   string
+}
+
+func NewResidentImportRepository(db *gorm.DB) domain.ResidentImportRepository {
+	return &residentImportRepository{
+		database:      db,
+		residentTable: domain.ResidentTable,
+		familyTable:   domain.FamilyTable,
+		memberTable:   domain.FamilyMemberTable,
+	}
+}
+
+func (r *residentImportRepository) ImportBatch(c context.Context, records []domain.ResidentImportRecord) (created int, updated int, err error) {
+	err = r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		created, updated = 0, 0
+
+		niks := make([]string, 0, len(records))
+		for _, record := range records {
+			niks = append(niks, record.Resident.NIK)
+		}
+		var existing []domain.Resident
+		if err := tx.Unscoped().Table(r.residentTable).Select("id", "nik", "region_id", "status", "deleted_at").Where("nik IN ?", niks).Find(&existing).Error; err != nil {
+			return err
+		}
+		if err := r.checkScope(c, tx, records); err != nil {
+			return err
+		}
+		current := make(map[string]domain.Resident, len(existing))
+		for _, resident := range existing {
+			current[resident.NIK] = resident
+		}
+		for _, record := range records {
+			if found, ok := current[record.Resident.NIK]; ok && found.DeletedAt.Valid {
+				return fmt.Errorf("row %d: nik %s belongs to a deleted resident, restore it first", record.Row, record.Resident.NIK)
+			}
+		}
+
+		// Penduduk baru dan penduduk terdaftar disimpan terpisah agar catatan
+		// audit membedakan create dan update beserta perubahan biodatanya.
+		residents := make([]domain.Resident, 0, len(records))
+		newResidents := make([]domain.Resident, 0, len(records))
+		isNew := make(map[uuid.UUID]bool, len(records))
+		for i := range records {
+			resident := &records[i].Resident
+			if found, ok := current[resident.NIK]; ok {
+				resident.ID = found.ID
+				err := tx.Table(r.residentTable).Where(queryFindByID, resident.ID).Select(residentImportColumns).Updates(*resident).Error
+				if err != nil {
+					return err
+				}
+				updated++
+			} else {
+				id, err := uuid.NewUUID()
+				if err != nil {
+					return err
+				}
+				resident.ID = id
+				resident.Status = domain.ResidentStatusActive
+				isNew[id] = true
+				newResidents = append(newResidents, *resident)
+				created++
+			}
+			residents = append(residents, *resident)
+		}
+		if len(newResidents) > 0 {
+			if err := tx.Table(r.residentTable).Create(&newResidents).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, resident := range residents {
+			if isNew[resident.ID] {
+				if err := openResidencePeriod(tx, resident.ID, resident.RegionID, resident.ResidenceStart()); err != nil {
+					return err
+				}
+				continue
+			}
+			// Perbedaan wilayah pada data impor dianggap koreksi sehingga
+			// masa tinggal yang sedang berlaku ikut dikoreksi.
+			if current[resident.NIK].RegionID != resident.RegionID {
+				err := tx.Table(domain.ResidencePeriodTable).
+					Where("resident_id = ? AND valid_until IS NULL", resident.ID).
+					Update("region_id", resident.RegionID).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		families := map[string]*domain.Family{}
+		for _, record := range records {
+			if record.Family == nil {
+				continue
+			}
+			if found, ok := current[record.Resident.NIK]; ok && found.Status != domain.ResidentStatusActive {
+				return fmt.Errorf("row %d: resident is no longer active and can not join a family", record.Row)
+			}
+			if err := r.importMembership(tx, families, record, isNew[record.Resident.ID]); err != nil {
+				return fmt.Errorf("row %d: %w", record.Row, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return created, updated, nil
+}
+
+// checkScope menolak baris yang akan mengubah penduduk atau KK yang terdaftar
+// di luar cakupan wilayah pengguna.
+func (r *residentImportRepository) checkScope(c context.Context, tx *gorm.DB, records []domain.ResidentImportRecord) error {
+	condition, args, restricted := regionScopeCondition(c, "region_id")
+	if !restricted {
+		return nil
+	}
+
+	niks := make([]string, 0, len(records))
+	kkNumbers := make([]string, 0, len(records))
+	for _, record := range records {
+		niks = append(niks, record.Resident.NIK)
+		if record.Family != nil {
+			kkNumbers = append(kkNumbers, record.Family.KKNumber)
+		}
+	}
+
+	var outside []string
+	if err := tx.Table(r.residentTable).Where("nik IN ?", niks).Where("NOT ("+condition+")", args...).Pluck("nik", &outside).Error; err != nil {
+		return err
+	}
+	for _, record := range records {
+		for _, nik := range outside {
+			if record.Resident.NIK == nik {
+				return fmt.Errorf("row %d: nik %s is registered outside your assigned area", record.Row, nik)
+			}
+		}
+	}
+	if len(kkNumbers) == 0 {
+		return nil
+	}
+
+	outside = nil
+	if err := tx.Table(r.familyTable).Where("kk_number IN ?", kkNumbers).Where("NOT ("+condition+")", args...).Pluck("kk_number", &outside).Error; err != nil {
+		return err
+	}
+	for _, record := range records {
+		for _, kkNumber := range outside {
+			if record.Family != nil && record.Family.KKNumber == kkNumber {
+				return fmt.Errorf("row %d: family %s is registered outside your assigned area", record.Row, kkNumber)
+			}
+		}
+	}
+	return nil
+}
+
+// importMembership memastikan KK pada baris impor tersedia dan penduduk
+// tercatat sebagai anggotanya. KK baru hanya dibuat dari baris kepala
+// keluarga. Keanggotaan di KK lain diakhiri per hari ini.
+func (r *residentImportRepository) importMembership(tx *gorm.DB, families map[string]*domain.Family, record domain.ResidentImportRecord, isNew bool) error {
+	resident := record.Resident
+	today := time.Now().In(domain.Location)
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, domain.Location)
+
+	family, ok := families[record.Family.KKNumber]
+	if !ok {
+		var found domain.Family
+		err := tx.Unscoped().Table(r.familyTable).Where("kk_number = ?", record.Family.KKNumber).First(&found).Error
+		switch {
+		case err == nil && found.DeletedAt.Valid:
+			return fmt.Errorf("family %s was deleted, restore it first", record.Family.KKNumber)
+		case err == nil:
+			family = &found
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if record.Relationship != domain.RelationshipKepalaKeluarga {
+				return fmt.Errorf("family %s not found, its kepala keluarga must be listed first", record.Family.KKNumber)
+			}
+			if record.Family.Address == "" {
+				return errors.New("address is required for a new family")
+			}
+			id, err := uuid.NewUUID()
+			if err != nil {
+				return err
+			}
+			family = record.Family
+			family.ID = id
+			family.HeadID = resident.ID
+			if err := tx.Table(r.familyTable).Omit("Members").Create(family).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+		families[family.KKNumber] = family
+	}
+
+	if err := lockResidents(tx, []uuid.UUID{resident.ID}); err != nil {
+		return err
+	}
+	var membership domain.FamilyMember
+	err := tx.Table(r.memberTable).Where("resident_id = ? AND valid_until IS NULL", resident.ID).Where(queryActiveFamily).First(&membership).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	found := err == nil
+
+	if record.Relationship == domain.RelationshipKepalaKeluarga && family.HeadID != resident.ID {
+		var heads int64
+		err := tx.Table(r.memberTable).
+			Where("family_id = ? AND relationship = ? AND valid_until IS NULL", family.ID, domain.RelationshipKepalaKeluarga).
+			Count(&heads).Error
+		if err != nil {
+			return err
+		}
+		if heads > 0 {
+			return fmt.Errorf("family %s already has a kepala keluarga", family.KKNumber)
+		}
+		if err := tx.Table(r.familyTable).Where(queryFindByID, family.ID).Update("head_id", resident.ID).Error; err != nil {
+			return err
+		}
+		family.HeadID = resident.ID
+	}
+
+	if found && membership.FamilyID == family.ID {
+		if membership.Relationship == record.Relationship {
+			return nil
+		}
+		return tx.Table(r.memberTable).Where(queryFindByID, membership.ID).Update("relationship", record.Relationship).Error
+	}
+	if found {
+		if membership.Relationship == domain.RelationshipKepalaKeluarga {
+			return errors.New("resident is kepala keluarga of another family")
+		}
+		if err := closeMemberships(tx, []uuid.UUID{resident.ID}, today); err != nil {
+			return err
+		}
+	}
+
+	validFrom := today
+	if isNew {
+		validFrom = resident.ResidenceStart()
+	}
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return err
+	}
+	member := domain.FamilyMember{
+		ID:           id,
+		FamilyID:     family.ID,
+		ResidentID:   resident.ID,
+		Relationship: record.Relationship,
+		ValidFrom:    validFrom,
+	}
+	return tx.Table(r.memberTable).Omit("Resident").Create(&member).Error
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+type residentRepository struct {
+	database  *gorm.DB
+	table     string
+	pageInit  int64
+	limitInit int64
+	builder   queryBuilder
+}
+
+func NewResidentRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.ResidentRepository {
+	return &residentRepository{
+		database:  db,
+		table:     table,
+		pageInit:  pageInit,
+		limitInit: limitInit,
+		builder: queryBuilder{
+			search: []searchColumn{{"name", searchContains}, {"nik", searchPrefix}},
+			filters: map[string]fieldFilter{
+				"sex":            {"sex", filterEquals},
+				"religion":       {"religion", filterIn},
+				"marital_status": {"marital_status", filterIn},
+				"education":      {"education", filterIn},
+				"occupation":     {"occupation", filterIn},
+				"blood_type":     {"blood_type", filterIn},
+				"status":         {"status", filterIn},
+				"region_id":      {"region_id", filterIn},
+				"region":         {"region_id", filterRegion},
+				"birth_date":     {"birth_date", filterDateRange},
+				"death_date":     {"death_date", filterDateRange},
+				"arrival_date":   {"arrival_date", filterDateRange},
+				"departure_date": {"departure_date", filterDateRange},
+				"created_at":     {"created_at", filterRange},
+			},
+			sorts: map[string]string{
+				"name":       "name",
+				"nik":        "nik",
+				"birth_date": "birth_date",
+				"created_at": "created_at",
+				"updated_at": "updated_at",
+			},
+			defaultSort: "name",
+			tiebreaker:  []string{"id"},
+			pageInit:    pageInit,
+			limitInit:   limitInit,
+		},
+	}
+}
+
+func (r *residentRepository) Create(c context.Context, data domain.Resident) error {
+	return r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(r.table).Create(&data).Error; err != nil {
+			return err
+		}
+		return openResidencePeriod(tx, data.ID, data.RegionID, data.ResidenceStart())
+	})
+}
+
+func (r *residentRepository) Retrieve(c context.Context, filter domain.Filter) (residents []domain.Resident, meta domain.MetaResponse, err error) {
+	asOf := func(db *gorm.DB) *gorm.DB {
+		if filter.AsOf.IsZero() {
+			return db
+		}
+		return db.Where("EXISTS (SELECT 1 FROM "+domain.ResidencePeriodTable+" AS rp WHERE rp.resident_id = "+r.table+".id AND "+validAt("rp")+")", filter.AsOf, filter.AsOf)
+	}
+
+	meta, err = r.builder.retrieve(r.scoped(c), filter, &residents, asOf)
+	if err != nil {
+		return nil, domain.MetaResponse{}, err
+	}
+	return residents, meta, nil
+}
+
+func (r *residentRepository) GetById(c context.Context, id uuid.UUID) (resident domain.Resident, err error) {
+	result := r.scoped(c).Where(queryFindByID, id).First(&resident)
+	if result.Error != nil {
+		return domain.Resident{}, result.Error
+	}
+	return resident, nil
+}
+
+func (r *residentRepository) GetByNIK(c context.Context, nik string) (resident domain.Resident, err error) {
+	result := r.scoped(c).Where("nik = ?", nik).First(&resident)
+	if result.Error != nil {
+		return domain.Resident{}, result.Error
+	}
+	return resident, nil
+}
+
+func (r *residentRepository) GetPeriods(c context.Context, residentID uuid.UUID) (periods []domain.ResidencePeriod, err error) {
+	query := r.database.WithContext(c).Table(domain.ResidencePeriodTable).Where("resident_id = ?", residentID)
+	if condition, args, restricted := regionScopeCondition(c, "region_id"); restricted {
+		query = query.Where("resident_id IN (SELECT id FROM "+r.table+" WHERE "+condition+")", args...)
+	}
+	result := query.Order("valid_from ASC").Find(&periods)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return periods, nil
+}
+
+// Update hanya untuk perbaikan biodata. Perubahan region_id dianggap koreksi
+// wilayah sehingga masa tinggal yang sedang berlaku ikut dikoreksi, sedangkan
+// perpindahan penduduk dicatat melalui alur pindah/datang.
+func (r *residentRepository) Update(c context.Context, id uuid.UUID, data domain.Resident) (resident domain.Resident, err error) {
+	err = r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		result := scopeRegion(c, tx.Table(r.table), "region_id").Where(queryFindByID, id).Omit("status", "death_date", "arrival_date", "departure_date").Updates(data)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no resident was updated")
+		}
+		if data.RegionID == uuid.Nil {
+			return nil
+		}
+		return tx.Table(domain.ResidencePeriodTable).
+			Where("resident_id = ? AND valid_until IS NULL", id).
+			Update("region_id", data.RegionID).Error
+	})
+	if err != nil {
+		return domain.Resident{}, err
+	}
+	return r.GetById(c, id)
+}
+
+// Delete hanya menandai penduduk terhapus. Masa tinggal dan keanggotaan KK
+// tetap disimpan agar ikut kembali saat penduduk dipulihkan, keduanya baru
+// dihapus permanen oleh Purge.
+func (r *residentRepository) Delete(c context.Context, id uuid.UUID) error {
+	result := r.scoped(c).Where(queryFindByID, id).Delete(&domain.Resident{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no resident was deleted")
+	}
+	return nil
+}
+
+func (r *residentRepository) Restore(c context.Context, id uuid.UUID) error {
+	result := r.scoped(c).Unscoped().Where(queryFindByID+" AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no deleted resident was restored")
+	}
+	return nil
+}
+
+// Purge menghapus permanen penduduk beserta masa tinggal, keanggotaan KK,
+// peristiwa kependudukan dan pasangan duplikat yang belum digabung. Riwayat
+// penggabungan tetap disimpan. KK yang dikepalai penduduk tersebut beralih ke
+// kepala keluarga lain yang masih tercatat, atau dikosongkan bila tidak ada.
+func (r *residentRepository) Purge(c context.Context, before time.Time) (purged int64, err error) {
+	err = r.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Unscoped().Table(r.table).Where("deleted_at < ?", before).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := detachHeads(tx, ids); err != nil {
+			return err
+		}
+		if err := tx.Table(domain.ResidencePeriodTable).Where("resident_id IN ?", ids).Delete(&domain.ResidencePeriod{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Table(domain.FamilyMemberTable).Where("resident_id IN ?", ids).Delete(&domain.FamilyMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Table(domain.MutationTable).Where("resident_id IN ?", ids).Delete(&domain.Mutation{}).Error; err != nil {
+			return err
+		}
+		err := tx.Table(domain.ResidentDuplicateTable).
+			Where("status <> ? AND (resident_id IN ? OR duplicate_id IN ?)", domain.ResidentDuplicateStatusMerged, ids, ids).
+			Delete(&domain.ResidentDuplicate{}).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Unscoped().Table(r.table).Where("id IN ?", ids).Delete(&domain.Resident{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+// detachHeads mengganti kepala KK yang akan dihapus permanen dengan anggota
+// lain yang masih tercatat sebagai kepala keluarga, atau mengosongkannya.
+func detachHeads(tx *gorm.DB, ids []uuid.UUID) error {
+	var familyIDs []uuid.UUID
+	if err := tx.Unscoped().Table(domain.FamilyTable).Where("head_id IN ?", ids).Pluck("id", &familyIDs).Error; err != nil {
+		return err
+	}
+	for _, familyID := range familyIDs {
+		var heads []uuid.UUID
+		err := tx.Table(domain.FamilyMemberTable).
+			Where("family_id = ? AND relationship = ? AND "+queryCurrentMember, familyID, domain.RelationshipKepalaKeluarga).
+			Where("resident_id NOT IN ?", ids).
+			Limit(1).
+			Pluck("resident_id", &heads).Error
+		if err != nil {
+			return err
+		}
+		var head interface{}
+		if len(heads) > 0 {
+			head = heads[0]
+		}
+		if err := tx.Unscoped().Table(domain.FamilyTable).Where(queryFindByID, familyID).Update("head_id", head).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scoped membatasi query penduduk pada cakupan wilayah pengguna.
+func (r *residentRepository) scoped(c context.Context) *gorm.DB {
+	return scopeRegion(c, r.database.WithContext(c).Table(r.table).Model(&domain.Resident{}), r.table+".region_id")
+}
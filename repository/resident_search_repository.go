@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+// residentSearchColumns adalah kolom penduduk beserta KK yang masih berlaku.
+const residentSearchColumns = "p.*, f.kk_number AS kk_number, f.address AS address"
+
+type residentSearchRepository struct {
+	database      *gorm.DB
+	residentTable string
+	memberTable   string
+	familyTable   string
+}
+
+func NewResidentSearchRepository(db *gorm.DB) domain.ResidentSearchRepository {
+	return &residentSearchRepository{
+		database:      db,
+		residentTable: domain.ResidentTable,
+		memberTable:   domain.FamilyMemberTable,
+		familyTable:   domain.FamilyTable,
+	}
+}
+
+func (r *residentSearchRepository) SearchName(c context.Context, terms []string, limit int) (results []domain.ResidentSearchResult, err error) {
+	return r.match(c, "p.name", terms, limit)
+}
+
+func (r *residentSearchRepository) SearchAddress(c context.Context, terms []string, limit int) (results []domain.ResidentSearchResult, err error) {
+	return r.match(c, "f.address", terms, limit)
+}
+
+func (r *residentSearchRepository) SearchNumber(c context.Context, prefix string, limit int) (results []domain.ResidentSearchResult, err error) {
+	result := r.query(c).
+		Select(residentSearchColumns+", 0 AS score").
+		Where("(p.nik LIKE ? OR f.kk_number LIKE ?)", prefix+"%", prefix+"%").
+		Order("p.nik").
+		Limit(limit).
+		Scan(&results)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return results, nil
+}
+
+// match mencari dengan MATCH ... AGAINST dalam BOOLEAN MODE. Setiap kata
+// dicari sebagai awalan tanpa operator wajib sehingga nama yang hanya cocok
+// sebagian tetap muncul dan diberi peringkat oleh usecase.
+func (r *residentSearchRepository) match(c context.Context, column string, terms []string, limit int) (results []domain.ResidentSearchResult, err error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	against := make([]string, len(terms))
+	for i, term := range terms {
+		against[i] = term + "*"
+	}
+	expression := "MATCH(" + column + ") AGAINST(? IN BOOLEAN MODE)"
+	pattern := strings.Join(against, " ")
+
+	result := r.query(c).
+		Select(residentSearchColumns+", "+expression+" AS score", pattern).
+		Where(expression, pattern).
+		Order("score DESC").
+		Limit(limit).
+		Scan(&results)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return results, nil
+}
+
+// query menggabungkan penduduk yang belum dihapus dengan KK yang masih
+// berlaku dan membatasinya pada wilayah pengguna.
+func (r *residentSearchRepository) query(c context.Context) *gorm.DB {
+	db := r.database.WithContext(c).
+		Table(r.residentTable + " AS p").
+		Joins("LEFT JOIN " + r.memberTable + " AS m ON m.resident_id = p.id AND m.valid_until IS NULL").
+		Joins("LEFT JOIN " + r.familyTable + " AS f ON f.id = m.family_id AND f.deleted_at IS NULL").
+		Where("p.deleted_at IS NULL")
+	return scopeRegion(c, db, "p.region_id")
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/koropati/population-recap/domain"
@@ -18,6 +20,7 @@ type userRepository struct {
 	table     string
 	pageInit  int64
 	limitInit int64
+	builder   queryBuilder
 }
 
 func NewUserRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.UserRepository {
@@ -26,6 +29,22 @@ func NewUserRepository(db *gorm.DB, table string, pageInit int64, limitInit int6
 		table:     table,
 		pageInit:  pageInit,
 		limitInit: limitInit,
+		builder: queryBuilder{
+			search: []searchColumn{{"name", searchContains}, {"email", searchContains}},
+			filters: map[string]fieldFilter{
+				"role":              {"role", filterIn},
+				"email_verified_at": {"email_verified_at", filterRange},
+			},
+			sorts: map[string]string{
+				"name":  "name",
+				"email": "email",
+				"role":  "role",
+			},
+			defaultSort: "name",
+			tiebreaker:  []string{"id"},
+			pageInit:    pageInit,
+			limitInit:   limitInit,
+		},
 	}
 }
 
@@ -38,34 +57,17 @@ func (u *userRepository) Create(c context.Context, data domain.User) error {
 }
 
 func (u *userRepository) Retrieve(c context.Context, filter domain.Filter) (users []domain.User, meta domain.MetaResponse, err error) {
-	query := u.database.WithContext(c).Table(u.table)
-
-	if filter.Search != "" {
-		query = query.Where("name LIKE ?", "%"+filter.Search+"%")
-	}
-
-	if filter.WithPagination {
-		offset := (filter.Page - 1) * filter.Limit
-		query = query.Offset(int(offset)).Limit(int(filter.Limit))
+	userRole := func(db *gorm.DB) *gorm.DB {
+		if filter.UserRole == "" {
+			return db
+		}
+		return db.Where("role = ?", filter.UserRole)
 	}
 
-	result := query.Find(&users)
-	if result.Error != nil {
-		return nil, domain.MetaResponse{}, result.Error
-	}
-
-	// Hitung total records
-	var totalRecords int64
-	u.database.Table(u.table).Count(&totalRecords)
-
-	meta = domain.MetaResponse{
-		TotalRecords:    totalRecords,
-		FilteredRecords: int64(result.RowsAffected),
-		Page:            filter.Page,
-		PerPage:         filter.Limit,
-		TotalPages:      (totalRecords + filter.Limit - 1) / filter.Limit,
+	meta, err = u.builder.retrieve(u.database.WithContext(c).Table(u.table).Model(&domain.User{}), filter, &users, userRole)
+	if err != nil {
+		return nil, domain.MetaResponse{}, err
 	}
-
 	return users, meta, nil
 }
 
@@ -89,17 +91,183 @@ func (u *userRepository) UpdatePassword(c context.Context, id uuid.UUID, newPass
 	return err
 }
 
-func (u *userRepository) Delete(c context.Context, id uuid.UUID) error {
-	result := u.database.WithContext(c).Table(u.table).Where(queryFindByID, id).Delete(&domain.User{})
+// VerifyEmail mencatat waktu verifikasi email dan mengaktifkan akun.
+func (u *userRepository) VerifyEmail(c context.Context, id uuid.UUID) (err error) {
+	result := u.database.WithContext(c).Table(u.table).Where(queryFindByID+" AND email_verified_at = ?", id, 0).
+		Updates(map[string]interface{}{"email_verified_at": time.Now().Unix(), "is_active": true})
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("no user was deleted")
+		return errors.New("email is already verified")
 	}
 	return nil
 }
 
+func (u *userRepository) UpdateStatus(c context.Context, id uuid.UUID, isActive bool) (err error) {
+	result := u.database.WithContext(c).Table(u.table).Where(queryFindByID, id).Update("is_active", isActive)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no user was updated")
+	}
+	return nil
+}
+
+func (u *userRepository) UpdateRole(c context.Context, id uuid.UUID, role string) (err error) {
+	result := u.database.WithContext(c).Table(u.table).Where(queryFindByID, id).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no user was updated")
+	}
+	return nil
+}
+
+func (u *userRepository) GetRegions(c context.Context, id uuid.UUID) (regions []domain.Region, err error) {
+	result := u.database.WithContext(c).Table(domain.RegionTable).
+		Joins("JOIN "+domain.UserRegionTable+" AS ur ON ur.region_id = "+domain.RegionTable+".id").
+		Where("ur.user_id = ?", id).
+		Order(domain.RegionTable + ".code ASC").
+		Find(&regions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return regions, nil
+}
+
+func (u *userRepository) GetAssignments(c context.Context, ids []uuid.UUID) (assignments []domain.UserRegion, err error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	result := u.database.WithContext(c).Table(domain.UserRegionTable).
+		Preload("Region").
+		Joins("JOIN "+domain.RegionTable+" AS r ON r.id = "+domain.UserRegionTable+".region_id").
+		Where(domain.UserRegionTable+".user_id IN ?", ids).
+		Order("r.code ASC").
+		Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
+// SetRegions mengganti wilayah tugas pengguna dalam satu transaksi sehingga
+// cakupan pengguna tidak pernah kosong sebagian.
+func (u *userRepository) SetRegions(c context.Context, id uuid.UUID, regionIDs []uuid.UUID) error {
+	unique := make([]uuid.UUID, 0, len(regionIDs))
+	seen := make(map[uuid.UUID]bool, len(regionIDs))
+	for _, regionID := range regionIDs {
+		if !seen[regionID] {
+			seen[regionID] = true
+			unique = append(unique, regionID)
+		}
+	}
+
+	return u.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if len(unique) > 0 {
+			var found int64
+			if err := tx.Table(domain.RegionTable).Where("id IN ?", unique).Count(&found).Error; err != nil {
+				return err
+			}
+			if found != int64(len(unique)) {
+				return errors.New("region not found")
+			}
+		}
+		if err := tx.Table(domain.UserRegionTable).Where("user_id = ?", id).Delete(&domain.UserRegion{}).Error; err != nil {
+			return err
+		}
+		if len(unique) == 0 {
+			return nil
+		}
+		assignments := make([]domain.UserRegion, 0, len(unique))
+		for _, regionID := range unique {
+			assignments = append(assignments, domain.UserRegion{UserID: id, RegionID: regionID})
+		}
+		return tx.Table(domain.UserRegionTable).Omit("Region").Create(&assignments).Error
+	})
+}
+
+// Delete menandai pengguna terhapus dan memberi akhiran pada emailnya agar
+// email tersebut dapat dipakai mendaftar lagi.
+func (u *userRepository) Delete(c context.Context, id uuid.UUID) error {
+	return u.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.Table(u.table).Where(queryFindByID, id).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("no user was deleted")
+			}
+			return err
+		}
+		if err := tx.Table(u.table).Where(queryFindByID, id).Update("email", domain.DeletedUserEmail(id, user.Email)).Error; err != nil {
+			return err
+		}
+		result := tx.Table(u.table).Where(queryFindByID, id).Delete(&domain.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no user was deleted")
+		}
+		return nil
+	})
+}
+
+func (u *userRepository) Restore(c context.Context, id uuid.UUID) error {
+	return u.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		err := tx.Unscoped().Table(u.table).Where(queryFindByID+" AND deleted_at IS NOT NULL", id).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("no deleted user was restored")
+		}
+		if err != nil {
+			return err
+		}
+
+		email := strings.TrimSuffix(user.Email, domain.DeletedEmailSeparator+id.String())
+		var taken int64
+		if err := tx.Table(u.table).Where("email = ?", email).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return errors.New("the email of this user is already used by another user")
+		}
+
+		result := tx.Unscoped().Table(u.table).Model(&domain.User{}).
+			Where(queryFindByID+" AND deleted_at IS NOT NULL", id).
+			Updates(map[string]interface{}{"email": email, "deleted_at": nil})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no deleted user was restored")
+		}
+		return nil
+	})
+}
+
+// Purge menghapus permanen pengguna beserta wilayah tugasnya.
+func (u *userRepository) Purge(c context.Context, before time.Time) (purged int64, err error) {
+	err = u.database.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Unscoped().Table(u.table).Where("deleted_at < ?", before).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := tx.Table(domain.UserRegionTable).Where("user_id IN ?", ids).Delete(&domain.UserRegion{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Table(u.table).Where("id IN ?", ids).Delete(&domain.User{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
 func (u *userRepository) GetByEmail(c context.Context, email string) (user domain.User, err error) {
 	result := u.database.WithContext(c).Table(u.table).Where("email = ?", email).First(&user)
 	if result.Error != nil {
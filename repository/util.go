@@ -1 +1,114 @@
 package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// validAt adalah syarat masa berlaku (valid_from sampai sebelum valid_until)
+// mencakup suatu tanggal. Kondisi ini membutuhkan tanggal acuan dua kali.
+func validAt(alias string) string {
+	return fmt.Sprintf("%[1]s.valid_from <= ? AND (%[1]s.valid_until IS NULL OR %[1]s.valid_until > ?)", alias)
+}
+
+// openResidencePeriod mencatat awal masa tinggal penduduk di suatu wilayah.
+func openResidencePeriod(tx *gorm.DB, residentID uuid.UUID, regionID uuid.UUID, from time.Time) error {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return err
+	}
+	period := domain.ResidencePeriod{ID: id, ResidentID: residentID, RegionID: regionID, ValidFrom: from}
+	return tx.Table(domain.ResidencePeriodTable).Create(&period).Error
+}
+
+// closeResidencePeriods mengakhiri masa tinggal yang masih berlaku.
+func closeResidencePeriods(tx *gorm.DB, residentIDs []uuid.UUID, until time.Time) error {
+	return tx.Table(domain.ResidencePeriodTable).
+		Where("resident_id IN ? AND valid_until IS NULL", residentIDs).
+		Update("valid_until", until).Error
+}
+
+// closeMemberships mengakhiri keanggotaan KK yang masih berlaku.
+func closeMemberships(tx *gorm.DB, residentIDs []uuid.UUID, until time.Time) error {
+	return tx.Table(domain.FamilyMemberTable).
+		Where("resident_id IN ? AND valid_until IS NULL", residentIDs).
+		Update("valid_until", until).Error
+}
+
+// lockNewMembers mengunci baris penduduk yang akan menjadi anggota KK sampai
+// transaksi selesai lalu memastikan belum ada yang tercatat di KK aktif,
+// sehingga dua permintaan bersamaan tidak menghasilkan dua keanggotaan yang
+// masih berlaku untuk penduduk yang sama.
+func lockNewMembers(tx *gorm.DB, residentIDs []uuid.UUID) error {
+	if len(residentIDs) == 0 {
+		return nil
+	}
+	if err := lockResidents(tx, residentIDs); err != nil {
+		return err
+	}
+
+	var members int64
+	err := tx.Table(domain.FamilyMemberTable).
+		Where("resident_id IN ? AND "+queryCurrentMember, residentIDs).
+		Where(queryActiveFamily).
+		Count(&members).Error
+	if err != nil {
+		return err
+	}
+	if members > 0 {
+		return domain.ErrAlreadyFamilyMember
+	}
+	return nil
+}
+
+// lockResidents mengunci baris penduduk sampai transaksi selesai.
+func lockResidents(tx *gorm.DB, residentIDs []uuid.UUID) error {
+	var locked []uuid.UUID
+	return tx.Table(domain.ResidentTable).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", residentIDs).
+		Pluck("id", &locked).Error
+}
+
+// regionScopeCondition menyusun syarat agar kolom region_id berada di dalam
+// cakupan wilayah pengguna pada context. restricted false berarti pengguna
+// tidak dibatasi dan syarat tidak perlu dipasang.
+func regionScopeCondition(c context.Context, column string) (condition string, args []interface{}, restricted bool) {
+	scope := domain.RegionScopeFromContext(c)
+	if !scope.Restricted {
+		return "", nil, false
+	}
+	if len(scope.Codes) == 0 {
+		return "1 = 0", nil, true
+	}
+	codeCondition, args := regionCodeCondition("code", scope.Codes)
+	return column + " IN (SELECT id FROM " + domain.RegionTable + " WHERE " + codeCondition + ")", args, true
+}
+
+// regionCodeCondition mencocokkan kolom kode wilayah dengan salah satu kode
+// beserta seluruh turunannya memanfaatkan prefix kode wilayah.
+func regionCodeCondition(column string, codes []string) (string, []interface{}) {
+	conditions := make([]string, 0, len(codes))
+	args := make([]interface{}, 0, len(codes)*2)
+	for _, code := range codes {
+		conditions = append(conditions, fmt.Sprintf("%[1]s = ? OR %[1]s LIKE ?", column))
+		args = append(args, code, code+".%")
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// scopeRegion membatasi query pada data dengan region_id di dalam cakupan
+// wilayah pengguna.
+func scopeRegion(c context.Context, db *gorm.DB, column string) *gorm.DB {
+	condition, args, restricted := regionScopeCondition(c, column)
+	if !restricted {
+		return db
+	}
+	return db.Where(condition, args...)
+}
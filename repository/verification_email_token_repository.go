@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+type verificationEmailTokenRepository struct {
+	database  *gorm.DB
+	table     string
+	pageInit  int64
+	limitInit int64
+}
+
+func NewVerificationEmailTokenRepository(db *gorm.DB, table string, pageInit int64, limitInit int64) domain.VerificationEmailTokenRepository {
+	return &verificationEmailTokenRepository{
+		database:  db,
+		table:     table,
+		pageInit:  pageInit,
+		limitInit: limitInit,
+	}
+}
+
+func (r *verificationEmailTokenRepository) Create(c context.Context, verificationEmailToken domain.VerificationEmailToken) error {
+	result := r.database.WithContext(c).Table(r.table).Create(&verificationEmailToken)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *verificationEmailTokenRepository) Revoke(c context.Context, token string) error {
+	result := r.database.WithContext(c).Table(r.table).Where("token = ?", token).Update("revoked", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no token was updated")
+	}
+	return nil
+}
+
+func (r *verificationEmailTokenRepository) RevokeByUserID(c context.Context, userID uuid.UUID) error {
+	result := r.database.WithContext(c).Table(r.table).Where("user_id = ?", userID).Update("revoked", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no token was updated")
+	}
+	return nil
+}
+
+func (r *verificationEmailTokenRepository) IsValid(c context.Context, token string) bool {
+	var verificationEmailToken domain.VerificationEmailToken
+	result := r.database.WithContext(c).Table(r.table).Where("token = ? AND revoked = ?", token, false).First(&verificationEmailToken)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false
+	}
+	return time.Unix(verificationEmailToken.ExpiresAt, 0).After(time.Now())
+}
+
+func (r *verificationEmailTokenRepository) Delete(c context.Context, token string) error {
+	result := r.database.WithContext(c).Table(r.table).Where("token = ?", token).Delete(&domain.VerificationEmailToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no token was deleted")
+	}
+	return nil
+}
+
+func (r *verificationEmailTokenRepository) GetUserID(c context.Context, token string) (userID uuid.UUID, err error) {
+	var verificationEmailToken domain.VerificationEmailToken
+	result := r.database.WithContext(c).Table(r.table).Where("token = ? AND revoked = ?", token, false).First(&verificationEmailToken)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return uuid.Nil, errors.New("failed get user id")
+	}
+	return verificationEmailToken.UserID, nil
+}
+
+func (r *verificationEmailTokenRepository) DeleteExpiredToken(c context.Context, millisDateTime int64) error {
+	result := r.database.WithContext(c).Table(r.table).Where("expires_at < ?", millisDateTime).Or("revoked = ?", 1).Delete(&domain.VerificationEmailToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewAuditLogRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	ac := newAuditLogController(cfg)
+
+	group.GET("/audit-logs", ac.Retrieve)
+	group.GET("/audit-logs/export", ac.Export)
+}
+
+// NewAuditLogPageRouter mendaftarkan halaman audit log untuk super_admin.
+func NewAuditLogPageRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	ac := newAuditLogController(cfg)
+
+	group.GET("/admin/audit-logs", ac.Page)
+	group.GET("/admin/audit-logs/export", ac.Export)
+}
+
+func newAuditLogController(cfg *SetupConfig) controller.AuditLogController {
+	ar := repository.NewAuditLogRepository(cfg.DB, domain.AuditLogTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	return controller.AuditLogController{
+		AuditLogUsecase: usecase.NewAuditLogUsecase(ar, cfg.Timeout),
+		Config:          cfg.Config,
+		Cryptos:         cfg.Cryptos,
+		Validator:       cfg.Validator,
+	}
+}
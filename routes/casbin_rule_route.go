@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewCasbinRuleRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	cc := newCasbinRuleController(cfg)
+
+	group.GET("/policies", cc.Retrieve)
+	group.POST("/policies", cc.AddPolicy)
+	group.POST("/policies/inheritances", cc.AddRoleInheritance)
+	group.DELETE("/policies/:id", cc.Delete)
+}
+
+// NewCasbinRulePageRouter mendaftarkan editor policy untuk super_admin.
+func NewCasbinRulePageRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	cc := newCasbinRuleController(cfg)
+
+	group.GET("/admin/policies", cc.Page)
+	group.POST("/admin/policies", cc.PageAddPolicy)
+	group.POST("/admin/policies/inheritances", cc.PageAddRoleInheritance)
+	group.POST("/admin/policies/:id/delete", cc.PageDelete)
+}
+
+func newCasbinRuleController(cfg *SetupConfig) controller.CasbinRuleController {
+	cr := repository.NewCasbinRuleRepository(cfg.DB, domain.CasbinRuleTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	return controller.CasbinRuleController{
+		CasbinRuleUsecase: usecase.NewCasbinRuleUsecase(cr, cfg.Timeout),
+		CasbinEnforcer:    cfg.CasbinEnforcer,
+		Config:            cfg.Config,
+		Cryptos:           cfg.Cryptos,
+		Validator:         cfg.Validator,
+	}
+}
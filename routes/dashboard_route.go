@@ -3,14 +3,25 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
 )
 
 func NewDashboardPageRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	gr := repository.NewRegionRepository(cfg.DB, domain.RegionTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	rr := repository.NewRecapRepository(cfg.DB)
 	sc := controller.DashboardController{
-		Config:    cfg.Config,
-		Cryptos:   cfg.Cryptos,
-		Validator: cfg.Validator,
+		RecapUsecase:  usecase.NewRecapUsecase(rr, gr, cfg.Timeout),
+		RegionUsecase: usecase.NewRegionUsecase(gr, cfg.Timeout),
+		Config:        cfg.Config,
+		Cryptos:       cfg.Cryptos,
+		Validator:     cfg.Validator,
 	}
 
 	group.GET("/dashboard", sc.Index)
+	group.GET("/dashboard/mutations", sc.Mutation)
+	group.GET("/dashboard/export/age-groups", sc.ExportAgeGroups)
+	group.GET("/dashboard/export/regions", sc.ExportRegions)
+	group.GET("/dashboard/export/mutations", sc.ExportMutation)
 }
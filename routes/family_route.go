@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewFamilyRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	fr := repository.NewFamilyRepository(cfg.DB, domain.FamilyTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	rr := repository.NewResidentRepository(cfg.DB, domain.ResidentTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	gr := repository.NewRegionRepository(cfg.DB, domain.RegionTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	fc := controller.FamilyController{
+		FamilyUsecase: usecase.NewFamilyUsecase(fr, rr, gr, cfg.Timeout),
+		Config:        cfg.Config,
+		Cryptos:       cfg.Cryptos,
+		Validator:     cfg.Validator,
+	}
+
+	group.GET("/families", fc.Retrieve)
+	group.POST("/families", fc.Create)
+	group.GET("/families/:id", fc.GetById)
+	group.PUT("/families/:id", fc.Update)
+	group.DELETE("/families/:id", fc.Delete)
+	group.POST("/families/:id/restore", fc.Restore)
+	group.POST("/families/:id/members", fc.AddMember)
+	group.DELETE("/families/:id/members/:resident_id", fc.RemoveMember)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewMigrationRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	mr := repository.NewMigrationRepository(cfg.DB, domain.MigrationTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	rr := repository.NewResidentRepository(cfg.DB, domain.ResidentTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	fr := repository.NewFamilyRepository(cfg.DB, domain.FamilyTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	gr := repository.NewRegionRepository(cfg.DB, domain.RegionTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	mc := controller.MigrationController{
+		MigrationUsecase: usecase.NewMigrationUsecase(mr, rr, fr, gr, cfg.Timeout),
+		Config:           cfg.Config,
+		Cryptos:          cfg.Cryptos,
+		Validator:        cfg.Validator,
+	}
+
+	group.GET("/migrations", mc.Retrieve)
+	group.POST("/migrations", mc.Create)
+	group.GET("/migrations/:id", mc.GetById)
+	group.GET("/migrations/:id/certificate", mc.Certificate)
+}
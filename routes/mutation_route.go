@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewMutationRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	mr := repository.NewMutationRepository(cfg.DB, domain.MutationTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	rr := repository.NewResidentRepository(cfg.DB, domain.ResidentTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	fr := repository.NewFamilyRepository(cfg.DB, domain.FamilyTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	gr := repository.NewRegionRepository(cfg.DB, domain.RegionTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	mc := controller.MutationController{
+		MutationUsecase: usecase.NewMutationUsecase(mr, rr, fr, gr, cfg.Timeout),
+		Config:          cfg.Config,
+		Cryptos:         cfg.Cryptos,
+		Validator:       cfg.Validator,
+	}
+
+	group.GET("/mutations", mc.Retrieve)
+	group.POST("/mutations/births", mc.RegisterBirth)
+	group.POST("/mutations/deaths", mc.RegisterDeath)
+	group.GET("/mutations/:id", mc.GetById)
+	group.DELETE("/mutations/:id", mc.Delete)
+	group.POST("/mutations/:id/restore", mc.Restore)
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewRecapRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	gr := repository.NewRegionRepository(cfg.DB, domain.RegionTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	rr := repository.NewRecapRepository(cfg.DB)
+	rc := controller.RecapController{
+		RecapUsecase: usecase.NewRecapUsecase(rr, gr, cfg.Timeout),
+		Config:       cfg.Config,
+		Cryptos:      cfg.Cryptos,
+		Validator:    cfg.Validator,
+	}
+
+	group.GET("/recaps/population", rc.Population)
+	group.GET("/recaps/mutations", rc.MonthlyMutation)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewRegionRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	gr := repository.NewRegionRepository(cfg.DB, domain.RegionTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	rc := controller.RegionController{
+		RegionUsecase: usecase.NewRegionUsecase(gr, cfg.Timeout),
+		Config:        cfg.Config,
+		Cryptos:       cfg.Cryptos,
+		Validator:     cfg.Validator,
+	}
+
+	group.GET("/regions", rc.Retrieve)
+	group.POST("/regions", rc.Create)
+	group.GET("/regions/:id", rc.GetById)
+	group.GET("/regions/:id/children", rc.GetChildren)
+	group.PUT("/regions/:id", rc.Update)
+	group.DELETE("/regions/:id", rc.Delete)
+}
@@ -10,13 +10,26 @@ import (
 
 func NewRegisterRouter(cfg *SetupConfig, group *gin.RouterGroup) {
 	ur := repository.NewUserRepository(cfg.DB, domain.UserTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	vt := repository.NewVerificationEmailTokenRepository(cfg.DB, domain.VerificationEmailTokenTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
 	sc := controller.RegisterController{
-		UserUsecase: usecase.NewUserUsecase(ur, cfg.Timeout),
-		Config:      cfg.Config,
-		Cryptos:     cfg.Cryptos,
-		Validator:   cfg.Validator,
+		UserUsecase:                   usecase.NewUserUsecase(ur, cfg.Timeout),
+		VerificationEmailTokenUsecase: usecase.NewVerificationEmailTokenUsecase(vt, cfg.Timeout),
+		Config:                        cfg.Config,
+		Cryptos:                       cfg.Cryptos,
+		Validator:                     cfg.Validator,
+		Mailer:                        cfg.Mailer,
+	}
+	vc := controller.VerificationEmailController{
+		UserUsecase:                   usecase.NewUserUsecase(ur, cfg.Timeout),
+		VerificationEmailTokenUsecase: usecase.NewVerificationEmailTokenUsecase(vt, cfg.Timeout),
+		Config:                        cfg.Config,
+		Cryptos:                       cfg.Cryptos,
+		Validator:                     cfg.Validator,
+		Mailer:                        cfg.Mailer,
 	}
 
 	group.GET("/register", sc.Index)
 	group.POST("/register", sc.Register)
+	group.GET("/verify-email", vc.VerifyEmail)
+	group.POST("/verify-email/resend", vc.ResendVerificationEmail)
 }
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewResidentDuplicateRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	dc := newResidentDuplicateController(cfg)
+
+	group.GET("/resident-duplicates", dc.Retrieve)
+	group.GET("/resident-duplicates/:id", dc.GetById)
+	group.POST("/resident-duplicates/:id/merge", dc.Merge)
+	group.POST("/resident-duplicates/:id/dismiss", dc.Dismiss)
+}
+
+// NewResidentDuplicatePageRouter mendaftarkan antrean tinjauan data ganda
+// untuk super_admin.
+func NewResidentDuplicatePageRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	dc := newResidentDuplicateController(cfg)
+
+	group.GET("/admin/resident-duplicates", dc.Page)
+	group.POST("/admin/resident-duplicates/:id/merge", dc.PageMerge)
+	group.POST("/admin/resident-duplicates/:id/dismiss", dc.PageDismiss)
+}
+
+func newResidentDuplicateController(cfg *SetupConfig) controller.ResidentDuplicateController {
+	dr := repository.NewResidentDuplicateRepository(cfg.DB, domain.ResidentDuplicateTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	return controller.ResidentDuplicateController{
+		ResidentDuplicateUsecase: usecase.NewResidentDuplicateUsecase(dr, cfg.Timeout),
+		Config:                   cfg.Config,
+		Cryptos:                  cfg.Cryptos,
+		Validator:                cfg.Validator,
+	}
+}
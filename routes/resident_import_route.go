@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewResidentImportRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	ir := repository.NewResidentImportRepository(cfg.DB)
+	gr := repository.NewRegionRepository(cfg.DB, domain.RegionTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	ic := controller.ResidentImportController{
+		ResidentImportUsecase: usecase.NewResidentImportUsecase(ir, gr, cfg.Validator, cfg.Timeout),
+		Config:                cfg.Config,
+		Cryptos:               cfg.Cryptos,
+		Validator:             cfg.Validator,
+	}
+
+	group.POST("/residents/import", ic.Import)
+	group.GET("/dashboard/import", ic.Page)
+	group.POST("/dashboard/import", ic.Upload)
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewResidentRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	rc := newResidentController(cfg)
+
+	group.GET("/residents", rc.Retrieve)
+	group.POST("/residents", rc.Create)
+	group.GET("/residents/search", rc.Search)
+	group.GET("/residents/:id", rc.GetById)
+	group.GET("/residents/:id/periods", rc.GetPeriods)
+	group.PUT("/residents/:id", rc.Update)
+	group.DELETE("/residents/:id", rc.Delete)
+	group.POST("/residents/:id/restore", rc.Restore)
+}
+
+// NewResidentPageRouter mendaftarkan halaman daftar penduduk.
+func NewResidentPageRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	rc := newResidentController(cfg)
+
+	group.GET("/residents", rc.Page)
+}
+
+func newResidentController(cfg *SetupConfig) controller.ResidentController {
+	rr := repository.NewResidentRepository(cfg.DB, domain.ResidentTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	gr := repository.NewRegionRepository(cfg.DB, domain.RegionTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	return controller.ResidentController{
+		ResidentUsecase:       usecase.NewResidentUsecase(rr, gr, cfg.Timeout),
+		ResidentSearchUsecase: usecase.NewResidentSearchUsecase(repository.NewResidentSearchRepository(cfg.DB), cfg.Timeout),
+		RegionUsecase:         usecase.NewRegionUsecase(gr, cfg.Timeout),
+		Config:                cfg.Config,
+		Cryptos:               cfg.Cryptos,
+		Validator:             cfg.Validator,
+	}
+}
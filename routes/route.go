@@ -30,7 +30,7 @@ type SetupConfig struct {
 	Config         *bootstrap.Config
 	Timeout        time.Duration
 	DB             *gorm.DB
-	CasbinEnforcer *casbin.Enforcer
+	CasbinEnforcer *casbin.SyncedEnforcer
 	Cryptos        cryptos.Cryptos
 	Gin            *gin.Engine
 	Validator      *validator.Validator
@@ -44,6 +44,7 @@ func Setup(config *SetupConfig) {
 
 	at := repository.NewAccessTokenRepository(config.DB, domain.AccessTokenTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
 	rt := repository.NewRefreshTokenRepository(config.DB, domain.RefreshTokenTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
+	ur := repository.NewUserRepository(config.DB, domain.UserTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
 
 	// All Public APIs
 	publicRouter := config.Gin.Group("/")
@@ -55,7 +56,36 @@ func Setup(config *SetupConfig) {
 	NewForgotPasswordRouter(config, publicRouter)
 
 	privateRouter := config.Gin.Group("/")
-	privateRouter.Use(middleware.AuthMiddleware(config.Config.AccessTokenSecret, config.CasbinEnforcer, config.Cryptos, usecase.NewAccessTokenUsecase(at, config.Timeout), usecase.NewRefreshTokenUsecase(rt, config.Timeout)))
+	privateRouter.Use(middleware.AuthMiddleware(config.Config, config.CasbinEnforcer, config.Cryptos, usecase.NewUserUsecase(ur, config.Timeout), usecase.NewAccessTokenUsecase(at, config.Timeout), usecase.NewRefreshTokenUsecase(rt, config.Timeout)))
+	privateRouter.Use(middleware.RegionScopeMiddleware(config.Cryptos, usecase.NewUserUsecase(ur, config.Timeout)))
+	privateRouter.Use(middleware.AuditActorMiddleware(config.Cryptos))
 	NewDashboardPageRouter(config, privateRouter)
+	NewFamilyRouter(config, privateRouter)
+	NewMutationRouter(config, privateRouter)
+	NewMigrationRouter(config, privateRouter)
+	NewResidentImportRouter(config, privateRouter)
+	NewResidentPageRouter(config, privateRouter)
+	NewUserPageRouter(config, privateRouter)
+	NewCasbinRulePageRouter(config, privateRouter)
+	NewAuditLogPageRouter(config, privateRouter)
+	NewResidentDuplicatePageRouter(config, privateRouter)
 
+	// API JSON berversi untuk aplikasi mobile dan instansi lain, diautentikasi
+	// dengan access token Bearer sebagai pengganti cookie sesi.
+	apiPublicRouter := config.Gin.Group("/api/v1")
+	NewAuthRouter(config, apiPublicRouter)
+
+	apiRouter := config.Gin.Group("/api/v1")
+	apiRouter.Use(middleware.JwtAuthMiddleware(config.Config.AccessTokenSecret, config.CasbinEnforcer, config.Cryptos, usecase.NewAccessTokenUsecase(at, config.Timeout), usecase.NewRefreshTokenUsecase(rt, config.Timeout)))
+	apiRouter.Use(middleware.RegionScopeMiddleware(config.Cryptos, usecase.NewUserUsecase(ur, config.Timeout)))
+	apiRouter.Use(middleware.AuditActorMiddleware(config.Cryptos))
+	NewUserRouter(config, apiRouter)
+	NewRegionRouter(config, apiRouter)
+	NewResidentRouter(config, apiRouter)
+	NewFamilyRouter(config, apiRouter)
+	NewMutationRouter(config, apiRouter)
+	NewRecapRouter(config, apiRouter)
+	NewCasbinRuleRouter(config, apiRouter)
+	NewAuditLogRouter(config, apiRouter)
+	NewResidentDuplicateRouter(config, apiRouter)
 }
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/koropati/population-recap/controller"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
+)
+
+func NewUserRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	uc := newUserController(cfg)
+
+	group.GET("/users", uc.Retrieve)
+	group.GET("/users/me", uc.Me)
+	group.GET("/users/:id", uc.GetById)
+	group.DELETE("/users/:id", uc.Delete)
+	group.POST("/users/:id/restore", uc.Restore)
+	group.PUT("/users/:id/activate", uc.Activate)
+	group.PUT("/users/:id/deactivate", uc.Deactivate)
+	group.PUT("/users/:id/role", uc.UpdateRole)
+	group.GET("/users/:id/regions", uc.GetRegions)
+	group.PUT("/users/:id/regions", uc.UpdateRegions)
+}
+
+// NewUserPageRouter mendaftarkan halaman pengelolaan pengguna untuk
+// super_admin.
+func NewUserPageRouter(cfg *SetupConfig, group *gin.RouterGroup) {
+	uc := newUserController(cfg)
+
+	group.GET("/admin/users", uc.Page)
+	group.POST("/admin/users/:id/activate", uc.PageActivate)
+	group.POST("/admin/users/:id/deactivate", uc.PageDeactivate)
+	group.POST("/admin/users/:id/role", uc.PageUpdateRole)
+	group.POST("/admin/users/:id/regions", uc.PageUpdateRegions)
+	group.POST("/admin/users/:id/delete", uc.PageDelete)
+	group.POST("/admin/users/:id/restore", uc.PageRestore)
+}
+
+func newUserController(cfg *SetupConfig) controller.UserController {
+	ur := repository.NewUserRepository(cfg.DB, domain.UserTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	at := repository.NewAccessTokenRepository(cfg.DB, domain.AccessTokenTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	rt := repository.NewRefreshTokenRepository(cfg.DB, domain.RefreshTokenTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	gr := repository.NewRegionRepository(cfg.DB, domain.RegionTable, cfg.Config.DefaultPageNumber, cfg.Config.DefaultPageSize)
+	return controller.UserController{
+		UserUsecase:         usecase.NewUserUsecase(ur, cfg.Timeout),
+		RegionUsecase:       usecase.NewRegionUsecase(gr, cfg.Timeout),
+		AccessTokenUsecase:  usecase.NewAccessTokenUsecase(at, cfg.Timeout),
+		RefreshTokenUsecase: usecase.NewRefreshTokenUsecase(rt, cfg.Timeout),
+		Config:              cfg.Config,
+		Cryptos:             cfg.Cryptos,
+		Validator:           cfg.Validator,
+	}
+}
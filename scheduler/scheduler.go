@@ -11,15 +11,20 @@ import (
 	"github.com/koropati/population-recap/internal/cryptos"
 	"github.com/koropati/population-recap/internal/mailer"
 	"github.com/koropati/population-recap/repository"
+	"github.com/koropati/population-recap/usecase"
 	"gopkg.in/robfig/cron.v2"
 	"gorm.io/gorm"
 )
 
+// defaultSoftDeleteRetentionDays dipakai bila SOFT_DELETE_RETENTION_DAYS tidak
+// diisi.
+const defaultSoftDeleteRetentionDays = 90
+
 type SetupConfig struct {
 	Config         *bootstrap.Config
 	Timeout        time.Duration
 	DB             *gorm.DB
-	CasbinEnforcer *casbin.Enforcer
+	CasbinEnforcer *casbin.SyncedEnforcer
 	Cryptos        cryptos.Cryptos
 	Mailer         mailer.Mailer
 }
@@ -41,6 +46,18 @@ func InitCron(config *SetupConfig) {
 		log.Print("Start Task TaskRemoveForgotPasswordToken()")
 		TaskRemoveForgotPasswordToken(config)
 	})
+	_, _ = sch.AddFunc("* * * * *", func() {
+		log.Print("Start Task TaskRemoveVerificationEmailToken()")
+		TaskRemoveVerificationEmailToken(config)
+	})
+	_, _ = sch.AddFunc("0 0 * * *", func() {
+		log.Print("Start Task TaskPurgeDeleted()")
+		TaskPurgeDeleted(config)
+	})
+	_, _ = sch.AddFunc("0 1 * * *", func() {
+		log.Print("Start Task TaskDetectDuplicateResidents()")
+		TaskDetectDuplicateResidents(config)
+	})
 
 	sch.Start()
 	<-stopChan
@@ -49,7 +66,7 @@ func InitCron(config *SetupConfig) {
 
 func TaskRemoveAccessToken(config *SetupConfig) {
 	at := repository.NewAccessTokenRepository(config.DB, domain.AccessTokenTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
-	err := at.DeleteExpiredToken(context.Background(), time.Now().UTC().UnixNano())
+	err := at.DeleteExpiredToken(context.Background(), time.Now().UTC().Unix())
 	if err != nil {
 		log.Printf("Error Delete Expired Access Token: %v\n", err)
 	}
@@ -57,7 +74,7 @@ func TaskRemoveAccessToken(config *SetupConfig) {
 
 func TaskRemoveRefrehToken(config *SetupConfig) {
 	rt := repository.NewRefreshTokenRepository(config.DB, domain.RefreshTokenTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
-	err := rt.DeleteExpiredToken(context.Background(), time.Now().UTC().UnixNano())
+	err := rt.DeleteExpiredToken(context.Background(), time.Now().UTC().Unix())
 	if err != nil {
 		log.Printf("Error Delete Expired Refresh Token: %v\n", err)
 	}
@@ -65,8 +82,66 @@ func TaskRemoveRefrehToken(config *SetupConfig) {
 
 func TaskRemoveForgotPasswordToken(config *SetupConfig) {
 	rt := repository.NewForgotPasswordTokenRepository(config.DB, domain.ForgotPasswordTokenTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
-	err := rt.DeleteExpiredToken(context.Background(), time.Now().UTC().UnixNano())
+	err := rt.DeleteExpiredToken(context.Background(), time.Now().UTC().Unix())
 	if err != nil {
 		log.Printf("Error Delete Expired Forgot Password Token: %v\n", err)
 	}
 }
+
+func TaskRemoveVerificationEmailToken(config *SetupConfig) {
+	vt := repository.NewVerificationEmailTokenRepository(config.DB, domain.VerificationEmailTokenTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
+	err := vt.DeleteExpiredToken(context.Background(), time.Now().UTC().Unix())
+	if err != nil {
+		log.Printf("Error Delete Expired Verification Email Token: %v\n", err)
+	}
+}
+
+// TaskPurgeDeleted menghapus permanen penduduk, keluarga, mutasi dan pengguna
+// yang sudah dihapus lebih lama dari masa retensi.
+func TaskPurgeDeleted(config *SetupConfig) {
+	retention := config.Config.SoftDeleteRetentionDays
+	if retention <= 0 {
+		retention = defaultSoftDeleteRetentionDays
+	}
+	before := time.Now().AddDate(0, 0, -retention)
+
+	rr := repository.NewResidentRepository(config.DB, domain.ResidentTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
+	if total, err := rr.Purge(context.Background(), before); err != nil {
+		log.Printf("Error Purge Deleted Resident: %v\n", err)
+	} else if total > 0 {
+		log.Printf("Purged %d Deleted Resident\n", total)
+	}
+
+	fr := repository.NewFamilyRepository(config.DB, domain.FamilyTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
+	if total, err := fr.Purge(context.Background(), before); err != nil {
+		log.Printf("Error Purge Deleted Family: %v\n", err)
+	} else if total > 0 {
+		log.Printf("Purged %d Deleted Family\n", total)
+	}
+
+	mr := repository.NewMutationRepository(config.DB, domain.MutationTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
+	if total, err := mr.Purge(context.Background(), before); err != nil {
+		log.Printf("Error Purge Deleted Mutation: %v\n", err)
+	} else if total > 0 {
+		log.Printf("Purged %d Deleted Mutation\n", total)
+	}
+
+	ur := repository.NewUserRepository(config.DB, domain.UserTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
+	if total, err := ur.Purge(context.Background(), before); err != nil {
+		log.Printf("Error Purge Deleted User: %v\n", err)
+	} else if total > 0 {
+		log.Printf("Purged %d Deleted User\n", total)
+	}
+}
+
+// TaskDetectDuplicateResidents mencari pasangan penduduk yang diduga orang
+// yang sama untuk ditinjau super_admin.
+func TaskDetectDuplicateResidents(config *SetupConfig) {
+	dr := repository.NewResidentDuplicateRepository(config.DB, domain.ResidentDuplicateTable, config.Config.DefaultPageNumber, config.Config.DefaultPageSize)
+	found, err := usecase.NewResidentDuplicateUsecase(dr, config.Timeout).Detect(context.Background())
+	if err != nil {
+		log.Printf("Error Detect Duplicate Resident: %v\n", err)
+	} else if found > 0 {
+		log.Printf("Found %d Probable Duplicate Resident\n", found)
+	}
+}
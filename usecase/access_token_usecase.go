@@ -26,12 +26,30 @@ func (a *accessTokenUsecase) Create(c context.Context, accessToken domain.Access
 	return a.accessTokenRepository.Create(ctx, accessToken)
 }
 
+func (a *accessTokenUsecase) GetByToken(c context.Context, token string) (domain.AccessToken, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.accessTokenRepository.GetByToken(ctx, token)
+}
+
 func (a *accessTokenUsecase) Revoke(c context.Context, token string) error {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	return a.accessTokenRepository.Revoke(ctx, token)
 }
 
+func (a *accessTokenUsecase) RevokeByID(c context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.accessTokenRepository.RevokeByID(ctx, id)
+}
+
+func (a *accessTokenUsecase) RevokeBySessionID(c context.Context, sessionID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.accessTokenRepository.RevokeBySessionID(ctx, sessionID)
+}
+
 func (a *accessTokenUsecase) RevokeByUserID(c context.Context, userID uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/spreadsheet"
+)
+
+type auditLogUsecase struct {
+	auditLogRepository domain.AuditLogRepository
+	contextTimeout     time.Duration
+}
+
+func NewAuditLogUsecase(auditLogRepository domain.AuditLogRepository, timeout time.Duration) domain.AuditLogUsecase {
+	return &auditLogUsecase{
+		auditLogRepository: auditLogRepository,
+		contextTimeout:     timeout,
+	}
+}
+
+func (u *auditLogUsecase) Retrieve(c context.Context, filter domain.AuditLogFilter) (logs []domain.AuditLog, meta domain.MetaResponse, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.auditLogRepository.Retrieve(ctx, filter)
+}
+
+func (u *auditLogUsecase) Export(c context.Context, filter domain.AuditLogFilter, format string, w io.Writer) error {
+	if !spreadsheet.IsSupported(format) {
+		return spreadsheet.ErrUnsupportedFormat
+	}
+
+	filter.WithPagination = false
+	logs, _, err := u.Retrieve(c, filter)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(logs))
+	for _, log := range logs {
+		rows = append(rows, []string{
+			time.Unix(log.CreatedAt, 0).Format("2006-01-02 15:04:05"),
+			log.ActorID,
+			log.ActorRole,
+			log.IPAddress,
+			log.Entity,
+			log.EntityID,
+			log.Action,
+			string(log.Before),
+			string(log.After),
+		})
+	}
+	return spreadsheet.Write(format, w, domain.AuditLogColumns, rows)
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/koropati/population-recap/domain"
+)
+
+type casbinRuleUsecase struct {
+	casbinRuleRepository domain.CasbinRuleRepository
+	contextTimeout       time.Duration
+}
+
+func NewCasbinRuleUsecase(casbinRuleRepository domain.CasbinRuleRepository, timeout time.Duration) domain.CasbinRuleUsecase {
+	return &casbinRuleUsecase{
+		casbinRuleRepository: casbinRuleRepository,
+		contextTimeout:       timeout,
+	}
+}
+
+func (u *casbinRuleUsecase) Retrieve(c context.Context, filter domain.Filter) (rules []domain.CasbinRule, meta domain.MetaResponse, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.casbinRuleRepository.Retrieve(ctx, filter)
+}
+
+func (u *casbinRuleUsecase) GetById(c context.Context, id uint) (rule domain.CasbinRule, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.casbinRuleRepository.GetById(ctx, id)
+}
+
+func (u *casbinRuleUsecase) AddPolicy(c context.Context, policy domain.CasbinPolicy) (domain.CasbinRule, error) {
+	rule := domain.CasbinRule{PType: domain.CasbinPolicyType, V0: policy.Role, V1: policy.Path, V2: policy.Method}
+	return u.create(c, rule)
+}
+
+func (u *casbinRuleUsecase) AddRoleInheritance(c context.Context, inheritance domain.CasbinRoleInheritance) (domain.CasbinRule, error) {
+	rule := domain.CasbinRule{PType: domain.CasbinGroupingType, V0: inheritance.Role, V1: inheritance.ParentRole}
+	return u.create(c, rule)
+}
+
+// Delete menolak penghapusan aturan akses penuh super_admin agar tidak ada
+// yang dapat mengunci seluruh pengelola dari editor policy.
+func (u *casbinRuleUsecase) Delete(c context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	rule, err := u.casbinRuleRepository.GetById(ctx, id)
+	if err != nil {
+		return errors.New("policy not found")
+	}
+	if isSuperAdminRule(rule) {
+		return errors.New("the super_admin full access policy can not be removed")
+	}
+	return u.casbinRuleRepository.Delete(ctx, id)
+}
+
+func (u *casbinRuleUsecase) create(c context.Context, rule domain.CasbinRule) (domain.CasbinRule, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	exists, err := u.casbinRuleRepository.Exists(ctx, rule)
+	if err != nil {
+		return domain.CasbinRule{}, err
+	}
+	if exists {
+		return domain.CasbinRule{}, errors.New("policy already exists")
+	}
+	if err := u.casbinRuleRepository.Create(ctx, &rule); err != nil {
+		return domain.CasbinRule{}, err
+	}
+	return rule, nil
+}
+
+func isSuperAdminRule(rule domain.CasbinRule) bool {
+	return rule.PType == domain.CasbinPolicyType && rule.V0 == domain.RoleSuperAdmin && rule.V1 == "/*" && rule.V2 == "*"
+}
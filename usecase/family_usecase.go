@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+type familyUsecase struct {
+	familyRepository   domain.FamilyRepository
+	residentRepository domain.ResidentRepository
+	regionRepository   domain.RegionRepository
+	contextTimeout     time.Duration
+}
+
+func NewFamilyUsecase(familyRepository domain.FamilyRepository, residentRepository domain.ResidentRepository, regionRepository domain.RegionRepository, timeout time.Duration) domain.FamilyUsecase {
+	return &familyUsecase{
+		familyRepository:   familyRepository,
+		residentRepository: residentRepository,
+		regionRepository:   regionRepository,
+		contextTimeout:     timeout,
+	}
+}
+
+func (u *familyUsecase) Create(c context.Context, family domain.Family) (domain.Family, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := checkResidentialRegion(ctx, u.regionRepository, family.RegionID); err != nil {
+		return domain.Family{}, err
+	}
+
+	familyID, err := uuid.NewUUID()
+	if err != nil {
+		return domain.Family{}, err
+	}
+	family.ID = familyID
+
+	// Kepala keluarga selalu menjadi anggota pertama, anggota lain tidak
+	// boleh memakai hubungan kepala_keluarga.
+	members := []domain.FamilyMember{{ResidentID: family.HeadID, Relationship: domain.RelationshipKepalaKeluarga}}
+	for _, member := range family.Members {
+		if member.ResidentID == family.HeadID {
+			continue
+		}
+		if member.Relationship == domain.RelationshipKepalaKeluarga {
+			return domain.Family{}, errors.New("a family can only have one kepala keluarga")
+		}
+		members = append(members, member)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(members))
+	for i := range members {
+		if seen[members[i].ResidentID] {
+			return domain.Family{}, errors.New("a resident is listed more than once")
+		}
+		seen[members[i].ResidentID] = true
+
+		resident, err := u.checkNewMember(ctx, members[i].ResidentID)
+		if err != nil {
+			return domain.Family{}, err
+		}
+		// Pendaftaran KK mencatat keadaan yang sudah ada sehingga keanggotaan
+		// bawaan berlaku sejak awal masa tinggal penduduk.
+		if members[i].ValidFrom.IsZero() {
+			members[i].ValidFrom = resident.ResidenceStart()
+		}
+		members[i].ValidUntil = nil
+
+		members[i].ID, err = uuid.NewUUID()
+		if err != nil {
+			return domain.Family{}, err
+		}
+		members[i].FamilyID = familyID
+		members[i].Resident = nil
+	}
+	family.Members = members
+
+	if err := u.familyRepository.Create(ctx, family); err != nil {
+		return domain.Family{}, err
+	}
+	return u.familyRepository.GetById(ctx, familyID)
+}
+
+func (u *familyUsecase) Retrieve(c context.Context, filter domain.Filter) (families []domain.Family, meta domain.MetaResponse, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.familyRepository.Retrieve(ctx, filter)
+}
+
+func (u *familyUsecase) GetById(c context.Context, id uuid.UUID) (family domain.Family, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.familyRepository.GetById(ctx, id)
+}
+
+func (u *familyUsecase) GetByKKNumber(c context.Context, kkNumber string) (family domain.Family, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.familyRepository.GetByKKNumber(ctx, kkNumber)
+}
+
+func (u *familyUsecase) Update(c context.Context, id uuid.UUID, data domain.Family) (domain.Family, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if data.RegionID != uuid.Nil {
+		if err := checkResidentialRegion(ctx, u.regionRepository, data.RegionID); err != nil {
+			return domain.Family{}, err
+		}
+	}
+	return u.familyRepository.Update(ctx, id, data)
+}
+
+func (u *familyUsecase) Delete(c context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.familyRepository.Delete(ctx, id)
+}
+
+func (u *familyUsecase) Restore(c context.Context, id uuid.UUID) (domain.Family, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := u.familyRepository.Restore(ctx, id); err != nil {
+		return domain.Family{}, err
+	}
+	return u.familyRepository.GetById(ctx, id)
+}
+
+func (u *familyUsecase) AddMember(c context.Context, familyID uuid.UUID, member domain.FamilyMember) (domain.Family, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if _, err := u.familyRepository.GetById(ctx, familyID); err != nil {
+		return domain.Family{}, err
+	}
+	if member.Relationship == domain.RelationshipKepalaKeluarga {
+		return domain.Family{}, errors.New("a family can only have one kepala keluarga")
+	}
+	if _, err := u.checkNewMember(ctx, member.ResidentID); err != nil {
+		return domain.Family{}, err
+	}
+	if member.ValidFrom.IsZero() {
+		member.ValidFrom = today()
+	}
+	member.ValidUntil = nil
+
+	memberID, err := uuid.NewUUID()
+	if err != nil {
+		return domain.Family{}, err
+	}
+	member.ID = memberID
+	member.FamilyID = familyID
+	member.Resident = nil
+
+	if err := u.familyRepository.AddMember(ctx, member); err != nil {
+		return domain.Family{}, err
+	}
+	return u.familyRepository.GetById(ctx, familyID)
+}
+
+func (u *familyUsecase) RemoveMember(c context.Context, familyID uuid.UUID, residentID uuid.UUID) (domain.Family, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	family, err := u.familyRepository.GetById(ctx, familyID)
+	if err != nil {
+		return domain.Family{}, err
+	}
+	if family.HeadID == residentID {
+		return domain.Family{}, errors.New("kepala keluarga can not be removed from the family")
+	}
+
+	if err := u.familyRepository.RemoveMember(ctx, familyID, residentID, today()); err != nil {
+		return domain.Family{}, err
+	}
+	return u.familyRepository.GetById(ctx, familyID)
+}
+
+// checkNewMember memastikan penduduk ada, masih aktif dan belum terdaftar di
+// KK lain.
+func (u *familyUsecase) checkNewMember(ctx context.Context, residentID uuid.UUID) (domain.Resident, error) {
+	resident, err := u.residentRepository.GetById(ctx, residentID)
+	if err != nil {
+		return domain.Resident{}, errors.New("resident not found")
+	}
+	if resident.Status != domain.ResidentStatusActive {
+		return domain.Resident{}, errors.New("resident is no longer active")
+	}
+
+	_, err = u.familyRepository.GetMemberByResidentID(ctx, residentID)
+	if err == nil {
+		return domain.Resident{}, domain.ErrAlreadyFamilyMember
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Resident{}, err
+	}
+	return resident, nil
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/validator"
+	"gorm.io/gorm"
+)
+
+type migrationUsecase struct {
+	migrationRepository domain.MigrationRepository
+	residentRepository  domain.ResidentRepository
+	familyRepository    domain.FamilyRepository
+	regionRepository    domain.RegionRepository
+	contextTimeout      time.Duration
+}
+
+func NewMigrationUsecase(migrationRepository domain.MigrationRepository, residentRepository domain.ResidentRepository, familyRepository domain.FamilyRepository, regionRepository domain.RegionRepository, timeout time.Duration) domain.MigrationUsecase {
+	return &migrationUsecase{
+		migrationRepository: migrationRepository,
+		residentRepository:  residentRepository,
+		familyRepository:    familyRepository,
+		regionRepository:    regionRepository,
+		contextTimeout:      timeout,
+	}
+}
+
+func (u *migrationUsecase) Create(c context.Context, request domain.MigrationRequest) (domain.Migration, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if request.MoveDate.After(time.Now()) {
+		return domain.Migration{}, errors.New("move date can not be in the future")
+	}
+
+	migration := domain.Migration{
+		Kind:                request.Kind,
+		FamilyID:            request.FamilyID,
+		DestinationRegionID: request.DestinationRegionID,
+		OriginAddress:       request.OriginAddress,
+		DestinationAddress:  request.DestinationAddress,
+		MoveDate:            request.MoveDate,
+		Reason:              request.Reason,
+	}
+	var err error
+	if migration.ID, err = uuid.NewUUID(); err != nil {
+		return domain.Migration{}, err
+	}
+
+	var arrivals []domain.Resident
+	switch request.Kind {
+	case domain.MigrationKindInternal, domain.MigrationKindDeparture:
+		if err := u.prepareMove(ctx, &migration, request); err != nil {
+			return domain.Migration{}, err
+		}
+	case domain.MigrationKindArrival:
+		if arrivals, err = u.prepareArrival(ctx, &migration, request); err != nil {
+			return domain.Migration{}, err
+		}
+	default:
+		return domain.Migration{}, errors.New("unknown migration kind")
+	}
+
+	if err := u.migrationRepository.Create(ctx, &migration, arrivals); err != nil {
+		return domain.Migration{}, err
+	}
+	return u.migrationRepository.GetById(ctx, migration.ID)
+}
+
+// prepareMove menyiapkan pindah di dalam atau ke luar Bangli. Seluruh
+// penduduk harus berasal dari wilayah yang sama, pindah di dalam Bangli
+// menghasilkan move_out di wilayah asal dan move_in di wilayah tujuan.
+func (u *migrationUsecase) prepareMove(ctx context.Context, migration *domain.Migration, request domain.MigrationRequest) error {
+	residents, err := u.movingResidents(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	origin := residents[0].RegionID
+	for _, resident := range residents {
+		if resident.RegionID != origin {
+			return errors.New("residents moving together must come from the same region")
+		}
+	}
+	migration.OriginRegionID = &origin
+
+	if request.Kind == domain.MigrationKindInternal {
+		if request.DestinationRegionID == nil {
+			return errors.New("destination region is required")
+		}
+		if *request.DestinationRegionID == origin {
+			return errors.New("destination region must differ from the origin region")
+		}
+		if _, err := residentialRegion(ctx, u.regionRepository, *request.DestinationRegionID); err != nil {
+			return err
+		}
+	} else {
+		if request.DestinationAddress == "" {
+			return errors.New("destination address is required")
+		}
+		migration.DestinationRegionID = nil
+	}
+
+	for _, resident := range residents {
+		if err := u.addMutation(migration, domain.MutationTypeMoveOut, resident.ID, origin); err != nil {
+			return err
+		}
+		if request.Kind == domain.MigrationKindInternal {
+			if err := u.addMutation(migration, domain.MutationTypeMoveIn, resident.ID, *request.DestinationRegionID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// movingResidents mengembalikan penduduk yang ikut pindah. Pindah satu KK
+// mencakup seluruh anggota yang masih aktif, sedangkan kepala keluarga tidak
+// boleh pindah perorangan selama masih ada anggota lain di KK-nya.
+func (u *migrationUsecase) movingResidents(ctx context.Context, request domain.MigrationRequest) ([]domain.Resident, error) {
+	residents := []domain.Resident{}
+
+	if request.FamilyID != nil {
+		if len(request.ResidentIDs) > 0 {
+			return nil, errors.New("fill either family_id or resident_ids, not both")
+		}
+		family, err := u.familyRepository.GetById(ctx, *request.FamilyID)
+		if err != nil {
+			return nil, errors.New("family not found")
+		}
+		for _, member := range family.Members {
+			if member.Resident != nil && member.Resident.Status == domain.ResidentStatusActive {
+				residents = append(residents, *member.Resident)
+			}
+		}
+		if len(residents) == 0 {
+			return nil, errors.New("family has no active members")
+		}
+		return residents, nil
+	}
+
+	if len(request.ResidentIDs) == 0 {
+		return nil, errors.New("family_id or resident_ids is required")
+	}
+	seen := make(map[uuid.UUID]bool, len(request.ResidentIDs))
+	for _, id := range request.ResidentIDs {
+		if seen[id] {
+			return nil, errors.New("a resident is listed more than once")
+		}
+		seen[id] = true
+
+		resident, err := u.residentRepository.GetById(ctx, id)
+		if err != nil {
+			return nil, errors.New("resident not found")
+		}
+		if resident.Status != domain.ResidentStatusActive {
+			return nil, errors.New("resident " + resident.NIK + " is not active")
+		}
+
+		member, err := u.familyRepository.GetMemberByResidentID(ctx, id)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err == nil && member.Relationship == domain.RelationshipKepalaKeluarga {
+			return nil, errors.New("kepala keluarga " + resident.NIK + " must move with the whole family")
+		}
+		residents = append(residents, resident)
+	}
+	return residents, nil
+}
+
+// prepareArrival menyiapkan penduduk baru yang datang dari luar Bangli.
+// Pengelompokan ke dalam KK dilakukan setelahnya melalui data keluarga.
+func (u *migrationUsecase) prepareArrival(ctx context.Context, migration *domain.Migration, request domain.MigrationRequest) ([]domain.Resident, error) {
+	if request.FamilyID != nil || len(request.ResidentIDs) > 0 {
+		return nil, errors.New("arrivals are registered through residents, not family_id or resident_ids")
+	}
+	if len(request.Residents) == 0 {
+		return nil, errors.New("residents are required")
+	}
+	if request.OriginAddress == "" {
+		return nil, errors.New("origin address is required")
+	}
+	if request.DestinationRegionID == nil {
+		return nil, errors.New("destination region is required")
+	}
+	if err := checkResidentialRegion(ctx, u.regionRepository, *request.DestinationRegionID); err != nil {
+		return nil, err
+	}
+
+	// NIK harus unik di seluruh wilayah, bukan hanya di wilayah tugas petugas.
+	unscoped := domain.WithRegionScope(ctx, domain.RegionScope{})
+	arrivalDate := request.MoveDate
+	arrivals := make([]domain.Resident, 0, len(request.Residents))
+	seen := make(map[string]bool, len(request.Residents))
+	for _, resident := range request.Residents {
+		if seen[resident.NIK] {
+			return nil, errors.New("nik " + resident.NIK + " is listed more than once")
+		}
+		seen[resident.NIK] = true
+
+		if err := validator.CheckNIKFormat(resident.NIK, resident.Sex, resident.BirthDate); err != nil {
+			return nil, errors.New("nik " + resident.NIK + ": " + err.Error())
+		}
+		if _, err := u.residentRepository.GetByNIK(unscoped, resident.NIK); err == nil {
+			return nil, errors.New("nik " + resident.NIK + " is already registered")
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return nil, err
+		}
+		resident.ID = id
+		resident.RegionID = *request.DestinationRegionID
+		resident.Status = domain.ResidentStatusActive
+		resident.DeathDate = nil
+		resident.DepartureDate = nil
+		resident.ArrivalDate = &arrivalDate
+		arrivals = append(arrivals, resident)
+
+		if err := u.addMutation(migration, domain.MutationTypeMoveIn, id, *request.DestinationRegionID); err != nil {
+			return nil, err
+		}
+	}
+	return arrivals, nil
+}
+
+func (u *migrationUsecase) addMutation(migration *domain.Migration, mutationType string, residentID uuid.UUID, regionID uuid.UUID) error {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return err
+	}
+	migrationID := migration.ID
+	migration.Mutations = append(migration.Mutations, domain.Mutation{
+		ID:          id,
+		Type:        mutationType,
+		ResidentID:  residentID,
+		RegionID:    regionID,
+		EventDate:   migration.MoveDate,
+		MigrationID: &migrationID,
+	})
+	return nil
+}
+
+func (u *migrationUsecase) Retrieve(c context.Context, filter domain.Filter) (migrations []domain.Migration, meta domain.MetaResponse, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.migrationRepository.Retrieve(ctx, filter)
+}
+
+func (u *migrationUsecase) GetById(c context.Context, id uuid.UUID) (migration domain.Migration, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.migrationRepository.GetById(ctx, id)
+}
+
+func (u *migrationUsecase) GetByCertificateNumber(c context.Context, certificateNumber string) (migration domain.Migration, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.migrationRepository.GetByCertificateNumber(ctx, certificateNumber)
+}
@@ -0,0 +1,272 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"gorm.io/gorm"
+)
+
+type mutationUsecase struct {
+	mutationRepository domain.MutationRepository
+	residentRepository domain.ResidentRepository
+	familyRepository   domain.FamilyRepository
+	regionRepository   domain.RegionRepository
+	contextTimeout     time.Duration
+}
+
+func NewMutationUsecase(mutationRepository domain.MutationRepository, residentRepository domain.ResidentRepository, familyRepository domain.FamilyRepository, regionRepository domain.RegionRepository, timeout time.Duration) domain.MutationUsecase {
+	return &mutationUsecase{
+		mutationRepository: mutationRepository,
+		residentRepository: residentRepository,
+		familyRepository:   familyRepository,
+		regionRepository:   regionRepository,
+		contextTimeout:     timeout,
+	}
+}
+
+func (u *mutationUsecase) RegisterBirth(c context.Context, registration domain.BirthRegistration) (domain.Mutation, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if registration.BirthDate.After(time.Now()) {
+		return domain.Mutation{}, errors.New("birth date can not be in the future")
+	}
+	family, err := u.familyRepository.GetById(ctx, registration.FamilyID)
+	if err != nil {
+		return domain.Mutation{}, errors.New("family not found")
+	}
+	// NIK harus unik di seluruh wilayah, bukan hanya di wilayah tugas petugas.
+	if _, err := u.residentRepository.GetByNIK(domain.WithRegionScope(ctx, domain.RegionScope{}), registration.NIK); err == nil {
+		return domain.Mutation{}, errors.New("nik is already registered")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Mutation{}, err
+	}
+
+	relationship := registration.Relationship
+	if relationship == "" {
+		relationship = domain.RelationshipAnak
+	}
+
+	// Nama orang tua yang tidak diisi diambil dari kepala keluarga dan
+	// pasangannya bila bayi adalah anak dalam KK tersebut.
+	fatherName, motherName := registration.FatherName, registration.MotherName
+	if relationship == domain.RelationshipAnak {
+		for _, member := range family.Members {
+			if member.Resident == nil {
+				continue
+			}
+			switch member.Relationship {
+			case domain.RelationshipKepalaKeluarga, domain.RelationshipSuami, domain.RelationshipIstri:
+			default:
+				continue
+			}
+			if fatherName == "" && member.Resident.Sex == domain.SexMale {
+				fatherName = member.Resident.Name
+			}
+			if motherName == "" && member.Resident.Sex == domain.SexFemale {
+				motherName = member.Resident.Name
+			}
+		}
+	}
+
+	resident := domain.Resident{
+		NIK:           registration.NIK,
+		Name:          registration.Name,
+		BirthPlace:    registration.BirthPlace,
+		BirthDate:     registration.BirthDate,
+		Sex:           registration.Sex,
+		Religion:      registration.Religion,
+		MaritalStatus: domain.MaritalStatusBelumKawin,
+		Education:     domain.EducationTidakSekolah,
+		Occupation:    "Belum/Tidak Bekerja",
+		BloodType:     registration.BloodType,
+		FatherName:    fatherName,
+		MotherName:    motherName,
+		RegionID:      family.RegionID,
+		Status:        domain.ResidentStatusActive,
+	}
+	if resident.ID, err = uuid.NewUUID(); err != nil {
+		return domain.Mutation{}, err
+	}
+
+	member := domain.FamilyMember{FamilyID: family.ID, ResidentID: resident.ID, Relationship: relationship, ValidFrom: registration.BirthDate}
+	if member.ID, err = uuid.NewUUID(); err != nil {
+		return domain.Mutation{}, err
+	}
+
+	mutation := domain.Mutation{
+		Type:       domain.MutationTypeBirth,
+		ResidentID: resident.ID,
+		RegionID:   family.RegionID,
+		EventDate:  registration.BirthDate,
+		Notes:      registration.Notes,
+	}
+	if mutation.ID, err = uuid.NewUUID(); err != nil {
+		return domain.Mutation{}, err
+	}
+
+	if err := u.mutationRepository.RegisterBirth(ctx, resident, member, mutation); err != nil {
+		return domain.Mutation{}, err
+	}
+	return u.mutationRepository.GetById(ctx, mutation.ID)
+}
+
+func (u *mutationUsecase) RegisterDeath(c context.Context, registration domain.DeathRegistration) (domain.Mutation, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	resident, err := u.residentRepository.GetById(ctx, registration.ResidentID)
+	if err != nil {
+		return domain.Mutation{}, errors.New("resident not found")
+	}
+	if resident.Status == domain.ResidentStatusDeceased {
+		return domain.Mutation{}, errors.New("resident is already recorded as deceased")
+	}
+	if registration.DeathDate.After(time.Now()) {
+		return domain.Mutation{}, errors.New("death date can not be in the future")
+	}
+	if registration.DeathDate.Before(resident.BirthDate) {
+		return domain.Mutation{}, errors.New("death date can not be before birth date")
+	}
+
+	var member, successor *domain.FamilyMember
+	current, err := u.familyRepository.GetMemberByResidentID(ctx, resident.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Mutation{}, err
+	}
+	if err == nil {
+		member = &current
+		if current.Relationship == domain.RelationshipKepalaKeluarga {
+			family, err := u.familyRepository.GetById(ctx, current.FamilyID)
+			if err != nil {
+				return domain.Mutation{}, err
+			}
+			// KK tanpa anggota lain tidak memiliki kepala keluarga pengganti
+			// dan tidak lagi dihitung sebagai rumah tangga sejak tanggal
+			// kematian.
+			successor, err = chooseSuccessor(family, resident.ID, registration.NewHeadID)
+			if err != nil {
+				return domain.Mutation{}, err
+			}
+		}
+	}
+
+	mutation := domain.Mutation{
+		Type:       domain.MutationTypeDeath,
+		ResidentID: resident.ID,
+		RegionID:   resident.RegionID,
+		EventDate:  registration.DeathDate,
+		Place:      registration.Place,
+		Cause:      registration.Cause,
+		Notes:      registration.Notes,
+	}
+	if mutation.ID, err = uuid.NewUUID(); err != nil {
+		return domain.Mutation{}, err
+	}
+
+	if err := u.mutationRepository.RegisterDeath(ctx, mutation, member, successor); err != nil {
+		return domain.Mutation{}, err
+	}
+	return u.mutationRepository.GetById(ctx, mutation.ID)
+}
+
+// successorOrder adalah urutan hubungan keluarga yang diutamakan menjadi
+// kepala keluarga pengganti.
+var successorOrder = []string{
+	domain.RelationshipIstri,
+	domain.RelationshipSuami,
+	domain.RelationshipAnak,
+	domain.RelationshipMenantu,
+	domain.RelationshipCucu,
+	domain.RelationshipOrangTua,
+	domain.RelationshipMertua,
+	domain.RelationshipFamiliLain,
+	domain.RelationshipLainnya,
+}
+
+// chooseSuccessor menentukan kepala keluarga pengganti. Pilihan petugas
+// (newHeadID) didahulukan, selain itu dipilih anggota dengan hubungan
+// terdekat dan yang tertua pada hubungan yang sama.
+func chooseSuccessor(family domain.Family, deceasedID uuid.UUID, newHeadID *uuid.UUID) (*domain.FamilyMember, error) {
+	candidates := make([]domain.FamilyMember, 0, len(family.Members))
+	for _, member := range family.Members {
+		if member.ResidentID == deceasedID || member.Resident == nil {
+			continue
+		}
+		if member.Resident.Status == domain.ResidentStatusDeceased {
+			continue
+		}
+		candidates = append(candidates, member)
+	}
+
+	if newHeadID != nil {
+		for i := range candidates {
+			if candidates[i].ResidentID == *newHeadID {
+				return &candidates[i], nil
+			}
+		}
+		return nil, errors.New("new head must be a living member of the same family")
+	}
+
+	var successor *domain.FamilyMember
+	rank := func(relationship string) int {
+		for i, candidate := range successorOrder {
+			if candidate == relationship {
+				return i
+			}
+		}
+		return len(successorOrder)
+	}
+	for i := range candidates {
+		candidate := &candidates[i]
+		if successor == nil {
+			successor = candidate
+			continue
+		}
+		candidateRank, successorRank := rank(candidate.Relationship), rank(successor.Relationship)
+		if candidateRank < successorRank || (candidateRank == successorRank && candidate.Resident.BirthDate.Before(successor.Resident.BirthDate)) {
+			successor = candidate
+		}
+	}
+	return successor, nil
+}
+
+func (u *mutationUsecase) Retrieve(c context.Context, filter domain.Filter) (mutations []domain.Mutation, meta domain.MetaResponse, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.mutationRepository.Retrieve(ctx, filter)
+}
+
+func (u *mutationUsecase) GetById(c context.Context, id uuid.UUID) (mutation domain.Mutation, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.mutationRepository.GetById(ctx, id)
+}
+
+func (u *mutationUsecase) Delete(c context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	mutation, err := u.mutationRepository.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if mutation.Type != domain.MutationTypeDeath {
+		return domain.ErrMutationNotDeletable
+	}
+	return u.mutationRepository.RevertDeath(ctx, mutation)
+}
+
+func (u *mutationUsecase) Restore(c context.Context, id uuid.UUID) (domain.Mutation, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := u.mutationRepository.Restore(ctx, id); err != nil {
+		return domain.Mutation{}, err
+	}
+	return u.mutationRepository.GetById(ctx, id)
+}
@@ -0,0 +1,534 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/report"
+)
+
+// recapLabels adalah label laporan untuk nilai isian biodata.
+var recapLabels = map[string]string{
+	domain.ReligionIslam:           "Islam",
+	domain.ReligionKristen:         "Kristen",
+	domain.ReligionKatolik:         "Katolik",
+	domain.ReligionHindu:           "Hindu",
+	domain.ReligionBuddha:          "Buddha",
+	domain.ReligionKonghucu:        "Konghucu",
+	domain.ReligionKepercayaan:     "Kepercayaan",
+	domain.EducationTidakSekolah:   "Tidak/Belum Sekolah",
+	domain.EducationBelumTamatSD:   "Belum Tamat SD/Sederajat",
+	domain.EducationSD:             "Tamat SD/Sederajat",
+	domain.EducationSLTP:           "SLTP/Sederajat",
+	domain.EducationSLTA:           "SLTA/Sederajat",
+	domain.EducationD1D2:           "Diploma I/II",
+	domain.EducationD3:             "Akademi/Diploma III",
+	domain.EducationD4S1:           "Diploma IV/Strata I",
+	domain.EducationS2:             "Strata II",
+	domain.EducationS3:             "Strata III",
+	domain.MaritalStatusBelumKawin: "Belum Kawin",
+	domain.MaritalStatusKawin:      "Kawin",
+	domain.MaritalStatusCeraiHidup: "Cerai Hidup",
+	domain.MaritalStatusCeraiMati:  "Cerai Mati",
+}
+
+var (
+	religionOrder      = []string{domain.ReligionIslam, domain.ReligionKristen, domain.ReligionKatolik, domain.ReligionHindu, domain.ReligionBuddha, domain.ReligionKonghucu, domain.ReligionKepercayaan}
+	educationOrder     = []string{domain.EducationTidakSekolah, domain.EducationBelumTamatSD, domain.EducationSD, domain.EducationSLTP, domain.EducationSLTA, domain.EducationD1D2, domain.EducationD3, domain.EducationD4S1, domain.EducationS2, domain.EducationS3}
+	maritalStatusOrder = []string{domain.MaritalStatusBelumKawin, domain.MaritalStatusKawin, domain.MaritalStatusCeraiHidup, domain.MaritalStatusCeraiMati}
+)
+
+type recapUsecase struct {
+	recapRepository  domain.RecapRepository
+	regionRepository domain.RegionRepository
+	contextTimeout   time.Duration
+}
+
+func NewRecapUsecase(recapRepository domain.RecapRepository, regionRepository domain.RegionRepository, timeout time.Duration) domain.RecapUsecase {
+	return &recapUsecase{
+		recapRepository:  recapRepository,
+		regionRepository: regionRepository,
+		contextTimeout:   timeout,
+	}
+}
+
+func (u *recapUsecase) Population(c context.Context, filter domain.RecapFilter) (recap domain.PopulationRecap, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	query, recap, err := u.resolveFilter(ctx, filter)
+	if err != nil {
+		return domain.PopulationRecap{}, err
+	}
+
+	regionRows, err := u.recapRepository.CountResidents(ctx, query, domain.RecapDimensionRegion)
+	if err != nil {
+		return domain.PopulationRecap{}, err
+	}
+	householdRows, err := u.recapRepository.CountHouseholds(ctx, query)
+	if err != nil {
+		return domain.PopulationRecap{}, err
+	}
+	recap.Regions, err = u.regionBreakdown(ctx, recap.GroupBy, regionRows, householdRows)
+	if err != nil {
+		return domain.PopulationRecap{}, err
+	}
+	for _, region := range recap.Regions {
+		recap.Total += region.Total
+		recap.Male += region.Male
+		recap.Female += region.Female
+		recap.Households += region.Households
+	}
+
+	ageRows, err := u.recapRepository.CountResidents(ctx, query, domain.RecapDimensionAgeGroup)
+	if err != nil {
+		return domain.PopulationRecap{}, err
+	}
+	recap.AgeGroups = buildRecapCounts(ageRows, ageGroupKeys(), ageGroupLabel)
+
+	breakdowns := []struct {
+		dimension string
+		order     []string
+		target    *[]domain.RecapCount
+	}{
+		{domain.RecapDimensionReligion, religionOrder, &recap.Religions},
+		{domain.RecapDimensionEducation, educationOrder, &recap.Educations},
+		{domain.RecapDimensionMaritalStatus, maritalStatusOrder, &recap.MaritalStatuses},
+		{domain.RecapDimensionOccupation, nil, &recap.Occupations},
+	}
+	for _, breakdown := range breakdowns {
+		rows, err := u.recapRepository.CountResidents(ctx, query, breakdown.dimension)
+		if err != nil {
+			return domain.PopulationRecap{}, err
+		}
+		*breakdown.target = buildRecapCounts(rows, breakdown.order, recapLabel)
+	}
+
+	return recap, nil
+}
+
+// MonthlyMutation menyusun neraca mutasi per desa untuk satu bulan laporan
+// dan menandai desa yang neracanya tidak sesuai dengan jumlah penduduk akhir
+// bulan.
+func (u *recapUsecase) MonthlyMutation(c context.Context, filter domain.MutationRecapFilter) (recap domain.MutationRecap, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	regionCode, err := scopedRegionCode(ctx, filter.RegionCode)
+	if err != nil {
+		return domain.MutationRecap{}, err
+	}
+	if filter.RegionCode == "" && len(regionCode) > domain.RegionCodeLength(domain.RegionLevelDesa) {
+		// Petugas dusun/banjar tetap mendapat neraca desanya yang dibatasi
+		// pada wilayah tugasnya oleh repository.
+		regionCode = ""
+	}
+
+	recap.RegionName = "Semua Wilayah"
+	if regionCode != "" {
+		region, err := u.regionRepository.GetByCode(ctx, regionCode)
+		if err != nil {
+			return domain.MutationRecap{}, errors.New("region not found")
+		}
+		if domain.RegionLevelDepth(region.Level) > domain.RegionLevelDepth(domain.RegionLevelDesa) {
+			return domain.MutationRecap{}, errors.New("mutation recap is reported per desa, select a kecamatan or desa")
+		}
+		recap.RegionCode = region.Code
+		recap.RegionName = region.Name
+	}
+
+	month := filter.Month
+	if month.IsZero() {
+		month = time.Now().In(domain.Location)
+	}
+	recap.PeriodStart = time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, domain.Location)
+	recap.PeriodEnd = recap.PeriodStart.AddDate(0, 1, -1)
+
+	query := domain.RecapQuery{
+		RegionCode:      recap.RegionCode,
+		GroupCodeLength: domain.RegionCodeLength(domain.RegionLevelDesa),
+	}
+
+	query.AsOf = recap.PeriodStart.AddDate(0, 0, -1)
+	openingRows, err := u.recapRepository.CountResidents(ctx, query, domain.RecapDimensionRegion)
+	if err != nil {
+		return domain.MutationRecap{}, err
+	}
+	query.AsOf = recap.PeriodEnd
+	closingRows, err := u.recapRepository.CountResidents(ctx, query, domain.RecapDimensionRegion)
+	if err != nil {
+		return domain.MutationRecap{}, err
+	}
+	mutationRows, err := u.recapRepository.CountMutations(ctx, query, recap.PeriodStart, recap.PeriodEnd)
+	if err != nil {
+		return domain.MutationRecap{}, err
+	}
+
+	byCode := map[string]*domain.MutationBalance{}
+	codes := []string{}
+	entry := func(code string) *domain.MutationBalance {
+		if _, ok := byCode[code]; !ok {
+			byCode[code] = &domain.MutationBalance{Code: code, Name: code}
+			codes = append(codes, code)
+		}
+		return byCode[code]
+	}
+	for _, row := range openingRows {
+		entry(row.Key).Opening += row.Total
+	}
+	for _, row := range closingRows {
+		entry(row.Key).Closing += row.Total
+	}
+	for _, row := range mutationRows {
+		balance := entry(row.Key)
+		switch row.Type {
+		case domain.MutationTypeBirth:
+			balance.Births += row.Total
+		case domain.MutationTypeDeath:
+			balance.Deaths += row.Total
+		case domain.MutationTypeMoveIn:
+			balance.MoveIns += row.Total
+		case domain.MutationTypeMoveOut:
+			balance.MoveOuts += row.Total
+		}
+	}
+
+	regions, err := u.regionRepository.GetByCodes(ctx, codes)
+	if err != nil {
+		return domain.MutationRecap{}, err
+	}
+	for _, region := range regions {
+		byCode[region.Code].Name = region.Name
+	}
+
+	sort.Strings(codes)
+	recap.Rows = make([]domain.MutationBalance, 0, len(codes))
+	recap.Total = domain.MutationBalance{Name: "Jumlah"}
+	for _, code := range codes {
+		balance := byCode[code]
+		reconcileBalance(balance)
+		if !balance.Reconciled {
+			recap.Unreconciled++
+		}
+		recap.Rows = append(recap.Rows, *balance)
+
+		recap.Total.Opening += balance.Opening
+		recap.Total.Births += balance.Births
+		recap.Total.Deaths += balance.Deaths
+		recap.Total.MoveIns += balance.MoveIns
+		recap.Total.MoveOuts += balance.MoveOuts
+		recap.Total.Closing += balance.Closing
+	}
+	reconcileBalance(&recap.Total)
+
+	return recap, nil
+}
+
+func reconcileBalance(balance *domain.MutationBalance) {
+	balance.Expected = balance.Opening + balance.Births - balance.Deaths + balance.MoveIns - balance.MoveOuts
+	balance.Difference = balance.Closing - balance.Expected
+	balance.Reconciled = balance.Difference == 0
+}
+
+// resolveFilter melengkapi nilai bawaan filter: tanpa wilayah berarti seluruh
+// wilayah, rincian bawaan satu level di bawah wilayah terpilih dan tanggal
+// acuan bawaan adalah hari ini.
+func (u *recapUsecase) resolveFilter(ctx context.Context, filter domain.RecapFilter) (query domain.RecapQuery, recap domain.PopulationRecap, err error) {
+	regionCode, err := scopedRegionCode(ctx, filter.RegionCode)
+	if err != nil {
+		return domain.RecapQuery{}, domain.PopulationRecap{}, err
+	}
+
+	level := ""
+	recap.RegionName = "Semua Wilayah"
+	if regionCode != "" {
+		region, err := u.regionRepository.GetByCode(ctx, regionCode)
+		if err != nil {
+			return domain.RecapQuery{}, domain.PopulationRecap{}, errors.New("region not found")
+		}
+		level = region.Level
+		recap.RegionCode = region.Code
+		recap.RegionName = region.Name
+	}
+
+	groupBy := filter.GroupBy
+	if groupBy == "" {
+		groupBy = domain.ChildRegionLevel(level)
+	}
+	if domain.RegionLevelDepth(groupBy) == 0 || domain.RegionLevelDepth(groupBy) < domain.RegionLevelDepth(level) {
+		return domain.RecapQuery{}, domain.PopulationRecap{}, errors.New("group_by must be at or below the selected region level")
+	}
+
+	asOf := today()
+	if !filter.AsOf.IsZero() {
+		asOf = dateOnly(filter.AsOf)
+	}
+
+	recap.GroupBy = groupBy
+	recap.AsOf = asOf
+	query = domain.RecapQuery{
+		RegionCode:      recap.RegionCode,
+		GroupCodeLength: domain.RegionCodeLength(groupBy),
+		AsOf:            asOf,
+	}
+	return query, recap, nil
+}
+
+// scopedRegionCode memastikan wilayah rekap berada di dalam cakupan wilayah
+// pengguna. Tanpa wilayah terpilih, pengguna dengan satu wilayah tugas
+// langsung mendapat rekap wilayah tugasnya.
+func scopedRegionCode(ctx context.Context, regionCode string) (string, error) {
+	scope := domain.RegionScopeFromContext(ctx)
+	if regionCode == "" {
+		if scope.Restricted && len(scope.Codes) == 1 {
+			return scope.Codes[0], nil
+		}
+		return "", nil
+	}
+	if !scope.Allows(regionCode) {
+		return "", domain.ErrRegionOutOfScope
+	}
+	return regionCode, nil
+}
+
+func (u *recapUsecase) regionBreakdown(ctx context.Context, groupBy string, residentRows []domain.RecapRow, householdRows []domain.RecapRow) ([]domain.RegionRecap, error) {
+	byCode := map[string]*domain.RegionRecap{}
+	codes := []string{}
+	entry := func(code string) *domain.RegionRecap {
+		if _, ok := byCode[code]; !ok {
+			byCode[code] = &domain.RegionRecap{Code: code, Name: code, Level: groupBy}
+			codes = append(codes, code)
+		}
+		return byCode[code]
+	}
+
+	for _, row := range residentRows {
+		region := entry(row.Key)
+		addSex(&region.Male, &region.Female, row)
+		region.Total += row.Total
+	}
+	for _, row := range householdRows {
+		entry(row.Key).Households += row.Total
+	}
+
+	regions, err := u.regionRepository.GetByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	for _, region := range regions {
+		byCode[region.Code].Name = region.Name
+		byCode[region.Code].Level = region.Level
+	}
+
+	sort.Strings(codes)
+	result := make([]domain.RegionRecap, 0, len(codes))
+	for _, code := range codes {
+		result = append(result, *byCode[code])
+	}
+	return result, nil
+}
+
+// buildRecapCounts menyusun baris agregat menjadi daftar berurutan. Kunci
+// pada order selalu muncul walaupun nol, kunci lain diurutkan dari jumlah
+// terbesar.
+func buildRecapCounts(rows []domain.RecapRow, order []string, label func(string) string) []domain.RecapCount {
+	byKey := map[string]*domain.RecapCount{}
+	keys := []string{}
+	entry := func(key string) *domain.RecapCount {
+		if _, ok := byKey[key]; !ok {
+			byKey[key] = &domain.RecapCount{Key: key, Label: label(key)}
+			keys = append(keys, key)
+		}
+		return byKey[key]
+	}
+
+	for _, key := range order {
+		entry(key)
+	}
+	for _, row := range rows {
+		count := entry(row.Key)
+		addSex(&count.Male, &count.Female, row)
+		count.Total += row.Total
+	}
+
+	extra := keys[len(order):]
+	sort.SliceStable(extra, func(i, j int) bool {
+		return byKey[extra[i]].Total > byKey[extra[j]].Total
+	})
+
+	result := make([]domain.RecapCount, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *byKey[key])
+	}
+	return result
+}
+
+func addSex(male *int64, female *int64, row domain.RecapRow) {
+	switch row.Sex {
+	case domain.SexMale:
+		*male += row.Total
+	case domain.SexFemale:
+		*female += row.Total
+	}
+}
+
+func recapLabel(key string) string {
+	if label, ok := recapLabels[key]; ok {
+		return label
+	}
+	return key
+}
+
+func ageGroupKeys() []string {
+	keys := make([]string, domain.AgeGroupCount)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys
+}
+
+func ageGroupLabel(key string) string {
+	index, err := strconv.Atoi(key)
+	if err != nil {
+		return key
+	}
+	lower := index * domain.AgeGroupSize
+	if index >= domain.AgeGroupCount-1 {
+		return fmt.Sprintf("%d+", lower)
+	}
+	return fmt.Sprintf("%d-%d", lower, lower+domain.AgeGroupSize-1)
+}
+
+// dateOnly membuang komponen jam karena seluruh tanggal kependudukan disimpan
+// sebagai DATE, pada zona waktu yang sama dengan koneksi database.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, domain.Location)
+}
+
+func today() time.Time {
+	return dateOnly(time.Now().In(domain.Location))
+}
+
+func (u *recapUsecase) PopulationReport(c context.Context, filter domain.RecapFilter, kind string) (domain.RecapReport, error) {
+	if kind == domain.RecapReportAgeGroups {
+		// Piramida penduduk cukup dihitung sekali untuk seluruh wilayah terpilih.
+		filter.GroupBy = ""
+	}
+	recap, err := u.Population(c, filter)
+	if err != nil {
+		return domain.RecapReport{}, err
+	}
+	kecamatan, err := u.kecamatanName(c, recap.RegionCode)
+	if err != nil {
+		return domain.RecapReport{}, err
+	}
+
+	result := domain.RecapReport{
+		Kecamatan:  kecamatan,
+		RegionName: recap.RegionName,
+		Period:     "Keadaan per " + report.FormatDate(recap.AsOf),
+	}
+	switch kind {
+	case domain.RecapReportAgeGroups:
+		result.Title = "Rekapitulasi Penduduk Menurut Kelompok Umur dan Jenis Kelamin"
+		table := domain.ReportTable{Header: []string{"Kelompok Umur", "Laki-laki", "Perempuan", "Jumlah"}}
+		for _, group := range recap.AgeGroups {
+			table.Rows = append(table.Rows, []string{group.Label, formatInt(group.Male), formatInt(group.Female), formatInt(group.Total)})
+		}
+		table.Footer = []string{"Jumlah", formatInt(recap.Male), formatInt(recap.Female), formatInt(recap.Total)}
+		result.Tables = []domain.ReportTable{table}
+	case domain.RecapReportRegions:
+		result.Title = "Rekapitulasi Jumlah Penduduk dan Kepala Keluarga per " + regionLevelLabel(recap.GroupBy)
+		table := domain.ReportTable{Header: []string{"No", "Kode", regionLevelLabel(recap.GroupBy), "Laki-laki", "Perempuan", "Jumlah", "KK"}}
+		for i, region := range recap.Regions {
+			table.Rows = append(table.Rows, []string{strconv.Itoa(i + 1), region.Code, region.Name, formatInt(region.Male), formatInt(region.Female), formatInt(region.Total), formatInt(region.Households)})
+		}
+		table.Footer = []string{"", "", "Jumlah", formatInt(recap.Male), formatInt(recap.Female), formatInt(recap.Total), formatInt(recap.Households)}
+		result.Tables = []domain.ReportTable{table}
+	default:
+		return domain.RecapReport{}, errors.New("unknown report " + kind)
+	}
+	return result, nil
+}
+
+func (u *recapUsecase) MutationReport(c context.Context, filter domain.MutationRecapFilter) (domain.RecapReport, error) {
+	recap, err := u.MonthlyMutation(c, filter)
+	if err != nil {
+		return domain.RecapReport{}, err
+	}
+	kecamatan, err := u.kecamatanName(c, recap.RegionCode)
+	if err != nil {
+		return domain.RecapReport{}, err
+	}
+
+	table := domain.ReportTable{Header: []string{"Kode", "Desa/Kelurahan", "Awal Bulan", "Lahir", "Mati", "Datang", "Pindah", "Akhir Bulan", "Selisih"}}
+	for _, row := range recap.Rows {
+		table.Rows = append(table.Rows, mutationReportRow(row.Code, row))
+	}
+	table.Footer = mutationReportRow("", recap.Total)
+
+	return domain.RecapReport{
+		Title:      "Laporan Mutasi Penduduk",
+		Kecamatan:  kecamatan,
+		RegionName: recap.RegionName,
+		Period:     "Bulan " + report.FormatMonth(recap.PeriodStart),
+		Tables:     []domain.ReportTable{table},
+	}, nil
+}
+
+// kecamatanName mencari nama kecamatan untuk kop laporan. Tanpa wilayah
+// terpilih seluruh kecamatan yang terdaftar dicantumkan.
+func (u *recapUsecase) kecamatanName(c context.Context, regionCode string) (string, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	length := domain.RegionCodeLength(domain.RegionLevelKecamatan)
+	if len(regionCode) >= length {
+		region, err := u.regionRepository.GetByCode(ctx, regionCode[:length])
+		if err != nil {
+			return "", errors.New("kecamatan not found")
+		}
+		return region.Name, nil
+	}
+
+	regions, _, err := u.regionRepository.Retrieve(ctx, domain.Filter{})
+	if err != nil {
+		return "", err
+	}
+	names := []string{}
+	for _, region := range regions {
+		if region.Level == domain.RegionLevelKecamatan {
+			names = append(names, region.Name)
+		}
+	}
+	return strings.Join(names, ", "), nil
+}
+
+func mutationReportRow(code string, balance domain.MutationBalance) []string {
+	return []string{
+		code, balance.Name, formatInt(balance.Opening), formatInt(balance.Births), formatInt(balance.Deaths),
+		formatInt(balance.MoveIns), formatInt(balance.MoveOuts), formatInt(balance.Closing), formatInt(balance.Difference),
+	}
+}
+
+func regionLevelLabel(level string) string {
+	switch level {
+	case domain.RegionLevelKecamatan:
+		return "Kecamatan"
+	case domain.RegionLevelDesa:
+		return "Desa/Kelurahan"
+	case domain.RegionLevelDusun:
+		return "Dusun/Banjar"
+	}
+	return "Wilayah"
+}
+
+func formatInt(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
@@ -26,16 +26,34 @@ func (a *refreshTokenUsecase) Create(c context.Context, refreshToken domain.Refr
 	return a.refreshTokenRepository.Create(ctx, refreshToken)
 }
 
+func (a *refreshTokenUsecase) GetByToken(c context.Context, token string) (domain.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.refreshTokenRepository.GetByToken(ctx, token)
+}
+
+func (a *refreshTokenUsecase) Rotate(c context.Context, token string) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.refreshTokenRepository.Rotate(ctx, token)
+}
+
+func (a *refreshTokenUsecase) RevokeBySessionID(c context.Context, sessionID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.refreshTokenRepository.RevokeBySessionID(ctx, sessionID)
+}
+
 func (a *refreshTokenUsecase) Revoke(c context.Context, token string) error {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	return a.refreshTokenRepository.Revoke(ctx, token)
 }
 
-func (a *refreshTokenUsecase) RevokeByPairToken(c context.Context, pairToken string) error {
+func (a *refreshTokenUsecase) RevokeByPairTokenID(c context.Context, pairTokenID string) error {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
-	return a.refreshTokenRepository.RevokeByPairToken(ctx, pairToken)
+	return a.refreshTokenRepository.RevokeByPairTokenID(ctx, pairTokenID)
 }
 
 func (a *refreshTokenUsecase) RevokeByUserID(c context.Context, userID uuid.UUID) error {
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+)
+
+type regionUsecase struct {
+	regionRepository domain.RegionRepository
+	contextTimeout   time.Duration
+}
+
+func NewRegionUsecase(regionRepository domain.RegionRepository, timeout time.Duration) domain.RegionUsecase {
+	return &regionUsecase{
+		regionRepository: regionRepository,
+		contextTimeout:   timeout,
+	}
+}
+
+func (u *regionUsecase) Create(c context.Context, region domain.Region) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err = u.validateHierarchy(ctx, region); err != nil {
+		return err
+	}
+
+	if region.ID == uuid.Nil {
+		region.ID, err = uuid.NewUUID()
+		if err != nil {
+			return err
+		}
+	}
+	return u.regionRepository.Create(ctx, region)
+}
+
+func (u *regionUsecase) Retrieve(c context.Context, filter domain.Filter) (regions []domain.Region, meta domain.MetaResponse, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.regionRepository.Retrieve(ctx, filter)
+}
+
+func (u *regionUsecase) GetById(c context.Context, id uuid.UUID) (region domain.Region, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.regionRepository.GetById(ctx, id)
+}
+
+func (u *regionUsecase) GetByCode(c context.Context, code string) (region domain.Region, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.regionRepository.GetByCode(ctx, code)
+}
+
+func (u *regionUsecase) GetByCodes(c context.Context, codes []string) (regions []domain.Region, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.regionRepository.GetByCodes(ctx, codes)
+}
+
+func (u *regionUsecase) GetChildren(c context.Context, parentID uuid.UUID) (regions []domain.Region, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.regionRepository.GetChildren(ctx, parentID)
+}
+
+func (u *regionUsecase) Update(c context.Context, id uuid.UUID, data domain.Region) (domain.Region, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	current, err := u.regionRepository.GetById(ctx, id)
+	if err != nil {
+		return domain.Region{}, err
+	}
+
+	// Level, induk dan kode menentukan posisi wilayah di hierarki, jadi
+	// validasi dilakukan terhadap gabungan data lama dan perubahan.
+	merged := current
+	if data.ParentID != nil {
+		merged.ParentID = data.ParentID
+	}
+	if data.Level != "" {
+		merged.Level = data.Level
+	}
+	if data.Type != "" {
+		merged.Type = data.Type
+	}
+	if data.Code != "" {
+		merged.Code = data.Code
+	}
+	if err := u.validateHierarchy(ctx, merged); err != nil {
+		return domain.Region{}, err
+	}
+
+	return u.regionRepository.Update(ctx, id, data)
+}
+
+func (u *regionUsecase) Delete(c context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.regionRepository.Delete(ctx, id)
+}
+
+// validateHierarchy memastikan jenis dan format kode wilayah sesuai levelnya
+// dan wilayah berada tepat satu level di bawah induknya dengan prefix kode
+// yang sama.
+func (u *regionUsecase) validateHierarchy(ctx context.Context, region domain.Region) error {
+	if !domain.IsValidRegionType(region.Level, region.Type) {
+		return errors.New("region type does not match its level")
+	}
+	if !domain.IsValidRegionCode(region.Level, region.Code) {
+		return errors.New("region code does not match the format of its level")
+	}
+
+	if region.Level == domain.RegionLevelKecamatan {
+		if region.ParentID != nil {
+			return errors.New("kecamatan can not have a parent region")
+		}
+		return nil
+	}
+
+	if region.ParentID == nil {
+		return errors.New("parent region is required")
+	}
+
+	parent, err := u.regionRepository.GetById(ctx, *region.ParentID)
+	if err != nil {
+		return errors.New("parent region not found")
+	}
+
+	if domain.RegionLevelDepth(region.Level) != domain.RegionLevelDepth(parent.Level)+1 {
+		return errors.New("region level must be directly below its parent")
+	}
+
+	if !strings.HasPrefix(region.Code, parent.Code+".") {
+		return errors.New("region code must start with its parent code")
+	}
+
+	return nil
+}
+
+// checkResidentialRegion memastikan penduduk dan keluarga hanya didaftarkan
+// pada desa/kelurahan atau dusun/banjar, bukan langsung pada kecamatan.
+func checkResidentialRegion(ctx context.Context, regionRepository domain.RegionRepository, regionID uuid.UUID) error {
+	region, err := residentialRegion(ctx, regionRepository, regionID)
+	if err != nil {
+		return err
+	}
+	if !domain.RegionScopeFromContext(ctx).Allows(region.Code) {
+		return domain.ErrRegionOutOfScope
+	}
+	return nil
+}
+
+// residentialRegion seperti checkResidentialRegion tanpa memeriksa cakupan
+// wilayah pengguna, dipakai untuk wilayah tujuan pindah yang boleh berada di
+// luar wilayah tugas petugas desa asal.
+func residentialRegion(ctx context.Context, regionRepository domain.RegionRepository, regionID uuid.UUID) (domain.Region, error) {
+	region, err := regionRepository.GetById(ctx, regionID)
+	if err != nil {
+		return domain.Region{}, errors.New("region not found")
+	}
+	if region.Level == domain.RegionLevelKecamatan {
+		return domain.Region{}, errors.New("region must be a desa/kelurahan or dusun/banjar")
+	}
+	return region, nil
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/balinese"
+)
+
+const (
+	// minDuplicateNameSimilarity masih menerima salah ketik kecil pada nama
+	// inti, misalnya "Sudarsna" dan "Sudarsana".
+	minDuplicateNameSimilarity = 0.7
+	// minDuplicateScore setara nama yang sedikit salah ketik ditambah tanggal
+	// lahir yang sama, atau nama yang sama ditambah kedua orang tua yang sama.
+	minDuplicateScore = 0.55
+	// maxDuplicateNIKDigits adalah jumlah digit NIK berbeda yang masih
+	// dianggap salah ketik.
+	maxDuplicateNIKDigits = 2
+
+	duplicateNameWeight      = 0.4
+	duplicateBirthDateWeight = 0.25
+	duplicateNIKWeight       = 0.2
+	duplicateParentWeight    = 0.075
+)
+
+type residentDuplicateUsecase struct {
+	residentDuplicateRepository domain.ResidentDuplicateRepository
+	contextTimeout              time.Duration
+}
+
+func NewResidentDuplicateUsecase(residentDuplicateRepository domain.ResidentDuplicateRepository, timeout time.Duration) domain.ResidentDuplicateUsecase {
+	return &residentDuplicateUsecase{
+		residentDuplicateRepository: residentDuplicateRepository,
+		contextTimeout:              timeout,
+	}
+}
+
+func (u *residentDuplicateUsecase) Retrieve(c context.Context, filter domain.Filter) (duplicates []domain.ResidentDuplicate, meta domain.MetaResponse, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.residentDuplicateRepository.Retrieve(ctx, filter)
+}
+
+func (u *residentDuplicateUsecase) GetById(c context.Context, id uuid.UUID) (duplicate domain.ResidentDuplicate, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.residentDuplicateRepository.GetById(ctx, id)
+}
+
+// Detect hanya membandingkan penduduk yang lahir pada tanggal yang sama atau
+// bernama inti sama, sehingga NIK yang salah ketik maupun tanggal lahir yang
+// salah ketik tetap ditemukan tanpa membandingkan seluruh pasangan penduduk.
+// Detect dijalankan oleh scheduler tanpa batas waktu request.
+func (u *residentDuplicateUsecase) Detect(c context.Context) (found int, err error) {
+	residents, err := u.residentDuplicateRepository.Candidates(c)
+	if err != nil {
+		return 0, err
+	}
+
+	groups := make(map[string][]int)
+	for i, resident := range residents {
+		birthDate := "birth_date:" + resident.BirthDate.Format("2006-01-02")
+		groups[birthDate] = append(groups[birthDate], i)
+		if core := balinese.Parse(resident.Name).Core; len(core) > 0 {
+			name := "name:" + strings.Join(core, " ")
+			groups[name] = append(groups[name], i)
+		}
+	}
+
+	compared := make(map[[2]uuid.UUID]bool)
+	for _, members := range groups {
+		for i := 0; i < len(members); i++ {
+			for j := i + 1; j < len(members); j++ {
+				a, b := residents[members[i]], residents[members[j]]
+				if b.ID.String() < a.ID.String() {
+					a, b = b, a
+				}
+				pair := [2]uuid.UUID{a.ID, b.ID}
+				if compared[pair] {
+					continue
+				}
+				compared[pair] = true
+
+				score, reasons := duplicateScore(a, b)
+				if score < minDuplicateScore {
+					continue
+				}
+				id, err := uuid.NewUUID()
+				if err != nil {
+					return found, err
+				}
+				err = u.residentDuplicateRepository.Record(c, domain.ResidentDuplicate{
+					ID:          id,
+					ResidentID:  a.ID,
+					DuplicateID: b.ID,
+					Score:       score,
+					Reasons:     strings.Join(reasons, ","),
+					Status:      domain.ResidentDuplicateStatusPending,
+				})
+				if err != nil {
+					return found, err
+				}
+				found++
+			}
+		}
+	}
+	return found, nil
+}
+
+func (u *residentDuplicateUsecase) Merge(c context.Context, id uuid.UUID, merge domain.ResidentDuplicateMerge) (domain.ResidentDuplicate, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	duplicate, err := u.residentDuplicateRepository.GetById(ctx, id)
+	if err != nil {
+		return domain.ResidentDuplicate{}, err
+	}
+	if merge.KeepID != duplicate.ResidentID && merge.KeepID != duplicate.DuplicateID {
+		return domain.ResidentDuplicate{}, errors.New("the resident to keep must be one of the duplicate pair")
+	}
+
+	duplicate.Status = domain.ResidentDuplicateStatusMerged
+	duplicate.KeptID = &merge.KeepID
+	duplicate.ReviewedBy = domain.AuditActorFromContext(ctx).UserID
+	duplicate.ReviewedAt = time.Now().Unix()
+	if err := u.residentDuplicateRepository.Merge(ctx, duplicate, time.Now()); err != nil {
+		return domain.ResidentDuplicate{}, err
+	}
+	return u.residentDuplicateRepository.GetById(ctx, id)
+}
+
+func (u *residentDuplicateUsecase) Dismiss(c context.Context, id uuid.UUID) (domain.ResidentDuplicate, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	duplicate, err := u.residentDuplicateRepository.GetById(ctx, id)
+	if err != nil {
+		return domain.ResidentDuplicate{}, err
+	}
+	duplicate.Status = domain.ResidentDuplicateStatusDismissed
+	duplicate.ReviewedBy = domain.AuditActorFromContext(ctx).UserID
+	duplicate.ReviewedAt = time.Now().Unix()
+	if err := u.residentDuplicateRepository.Dismiss(ctx, duplicate); err != nil {
+		return domain.ResidentDuplicate{}, err
+	}
+	return u.residentDuplicateRepository.GetById(ctx, id)
+}
+
+// duplicateScore menilai kemungkinan dua penduduk adalah orang yang sama
+// beserta alasannya. Nama harus mirip, sedangkan tanggal lahir, NIK yang
+// hanya berbeda sedikit dan nama orang tua menambah keyakinan.
+func duplicateScore(a domain.Resident, b domain.Resident) (float64, []string) {
+	// Nama urutan kelahiran yang berbeda, misalnya Wayan Sari dan Made Sari,
+	// adalah kakak beradik atau anak kembar, bukan data ganda.
+	first, second := balinese.Parse(a.Name).BirthOrder, balinese.Parse(b.Name).BirthOrder
+	if first != 0 && second != 0 && first != second {
+		return 0, nil
+	}
+
+	similarity := balinese.Similarity(a.Name, b.Name)
+	if similarity < minDuplicateNameSimilarity {
+		return 0, nil
+	}
+	score := duplicateNameWeight * similarity
+	reasons := []string{domain.DuplicateReasonName}
+
+	if a.BirthDate.Equal(b.BirthDate) {
+		score += duplicateBirthDateWeight
+		reasons = append(reasons, domain.DuplicateReasonBirthDate)
+	}
+	if differentDigits(a.NIK, b.NIK) <= maxDuplicateNIKDigits {
+		score += duplicateNIKWeight
+		reasons = append(reasons, domain.DuplicateReasonNIK)
+	}
+
+	var parents int
+	for _, names := range [][2]string{{a.FatherName, b.FatherName}, {a.MotherName, b.MotherName}} {
+		if names[0] != "" && names[1] != "" && balinese.Similarity(names[0], names[1]) >= minDuplicateNameSimilarity {
+			parents++
+		}
+	}
+	if parents > 0 {
+		score += duplicateParentWeight * float64(parents)
+		reasons = append(reasons, domain.DuplicateReasonParents)
+	}
+
+	sort.Strings(reasons)
+	return score, reasons
+}
+
+// differentDigits menghitung posisi digit yang berbeda pada dua NIK yang sama
+// panjang, NIK yang berbeda panjang dianggap berbeda seluruhnya.
+func differentDigits(a string, b string) int {
+	if len(a) != len(b) {
+		return len(a) + len(b)
+	}
+	var different int
+	for i := range a {
+		if a[i] != b[i] {
+			different++
+		}
+	}
+	return different
+}
@@ -0,0 +1,371 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	playground "github.com/go-playground/validator/v10"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/spreadsheet"
+	"github.com/koropati/population-recap/internal/validator"
+)
+
+// importRequiredColumns adalah kolom yang wajib ada pada header berkas.
+var importRequiredColumns = []string{
+	"nik", "name", "birth_place", "birth_date", "sex", "religion", "marital_status",
+	"education", "occupation", "region_code",
+}
+
+// importSexValues menerima penulisan jenis kelamin yang lazim pada berkas desa.
+var importSexValues = map[string]string{
+	"l":         domain.SexMale,
+	"lk":        domain.SexMale,
+	"laki-laki": domain.SexMale,
+	"laki_laki": domain.SexMale,
+	"male":      domain.SexMale,
+	"p":         domain.SexFemale,
+	"pr":        domain.SexFemale,
+	"perempuan": domain.SexFemale,
+	"wanita":    domain.SexFemale,
+	"female":    domain.SexFemale,
+}
+
+type residentImportUsecase struct {
+	importRepository domain.ResidentImportRepository
+	regionRepository domain.RegionRepository
+	validator        *validator.Validator
+	contextTimeout   time.Duration
+}
+
+func NewResidentImportUsecase(importRepository domain.ResidentImportRepository, regionRepository domain.RegionRepository, validator *validator.Validator, timeout time.Duration) domain.ResidentImportUsecase {
+	return &residentImportUsecase{
+		importRepository: importRepository,
+		regionRepository: regionRepository,
+		validator:        validator,
+		contextTimeout:   timeout,
+	}
+}
+
+func (u *residentImportUsecase) Import(c context.Context, format string, file io.Reader) (domain.ImportResult, error) {
+	lines, err := spreadsheet.Read(format, file)
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+	if len(lines) == 0 {
+		return domain.ImportResult{}, errors.New("file is empty")
+	}
+	columns, err := importColumnIndex(lines[0])
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	result := domain.ImportResult{Errors: []domain.ImportRowError{}}
+	rows := []domain.ResidentImportRow{}
+	seen := map[string]int{}
+	for i, line := range lines[1:] {
+		if isBlankLine(line) {
+			continue
+		}
+		result.TotalRows++
+		row, err := parseImportRow(i+2, line, columns)
+		if err == nil {
+			err = u.validateImportRow(row)
+		}
+		if err == nil {
+			if first, ok := seen[row.NIK]; ok {
+				err = fmt.Errorf("nik is listed more than once, first on row %d", first)
+			}
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, domain.ImportRowError{Row: i + 2, NIK: row.NIK, Name: row.Name, Message: err.Error()})
+			continue
+		}
+		seen[row.NIK] = row.Row
+		rows = append(rows, row)
+	}
+
+	records, rowErrors, err := u.buildRecords(c, rows)
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+	result.Errors = append(result.Errors, rowErrors...)
+
+	// Kepala keluarga disimpan lebih dulu agar KK baru sudah tersedia saat
+	// anggota lainnya diproses.
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Relationship == domain.RelationshipKepalaKeluarga && records[j].Relationship != domain.RelationshipKepalaKeluarga
+	})
+
+	for start := 0; start < len(records); start += domain.ImportBatchSize {
+		end := start + domain.ImportBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		u.importBatch(c, records[start:end], &result)
+	}
+
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Row < result.Errors[j].Row
+	})
+	result.Failed = len(result.Errors)
+	return result, nil
+}
+
+// importBatch menyimpan satu batch dalam satu transaksi. Bila batch gagal,
+// setiap baris disimpan ulang satu per satu agar hanya baris yang bermasalah
+// yang tercatat gagal.
+func (u *residentImportUsecase) importBatch(c context.Context, records []domain.ResidentImportRecord, result *domain.ImportResult) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	created, updated, err := u.importRepository.ImportBatch(ctx, records)
+	cancel()
+	if err == nil {
+		result.Created += created
+		result.Updated += updated
+		return
+	}
+	if len(records) == 1 {
+		result.Errors = append(result.Errors, domain.ImportRowError{
+			Row:     records[0].Row,
+			NIK:     records[0].Resident.NIK,
+			Name:    records[0].Resident.Name,
+			Message: strings.TrimPrefix(err.Error(), fmt.Sprintf("row %d: ", records[0].Row)),
+		})
+		return
+	}
+	for i := range records {
+		u.importBatch(c, records[i:i+1], result)
+	}
+}
+
+// buildRecords mencocokkan kode wilayah dan menyusun data penduduk serta KK.
+func (u *residentImportUsecase) buildRecords(c context.Context, rows []domain.ResidentImportRow) ([]domain.ResidentImportRecord, []domain.ImportRowError, error) {
+	codes := []string{}
+	for _, row := range rows {
+		codes = append(codes, row.RegionCode)
+	}
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	regions, err := u.regionRepository.GetByCodes(ctx, codes)
+	cancel()
+	if err != nil {
+		return nil, nil, err
+	}
+	byCode := make(map[string]domain.Region, len(regions))
+	for _, region := range regions {
+		byCode[region.Code] = region
+	}
+
+	scope := domain.RegionScopeFromContext(c)
+	records := make([]domain.ResidentImportRecord, 0, len(rows))
+	rowErrors := []domain.ImportRowError{}
+	for _, row := range rows {
+		region, ok := byCode[row.RegionCode]
+		if !ok || region.Level == domain.RegionLevelKecamatan {
+			message := "region code " + row.RegionCode + " is not a registered desa or banjar"
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: row.Row, NIK: row.NIK, Name: row.Name, Message: message})
+			continue
+		}
+		if !scope.Allows(region.Code) {
+			message := "region code " + row.RegionCode + " is outside your assigned area"
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: row.Row, NIK: row.NIK, Name: row.Name, Message: message})
+			continue
+		}
+
+		record := domain.ResidentImportRecord{
+			Row:          row.Row,
+			Relationship: row.Relationship,
+			Resident: domain.Resident{
+				NIK:           row.NIK,
+				Name:          row.Name,
+				BirthPlace:    row.BirthPlace,
+				BirthDate:     row.BirthDate,
+				Sex:           row.Sex,
+				Religion:      row.Religion,
+				MaritalStatus: row.MaritalStatus,
+				Education:     row.Education,
+				Occupation:    row.Occupation,
+				BloodType:     row.BloodType,
+				FatherName:    row.FatherName,
+				MotherName:    row.MotherName,
+				RegionID:      region.ID,
+			},
+		}
+		if row.KKNumber != "" {
+			record.Family = &domain.Family{
+				KKNumber: row.KKNumber,
+				Address:  row.Address,
+				RT:       row.RT,
+				RW:       row.RW,
+				RegionID: region.ID,
+			}
+		}
+		records = append(records, record)
+	}
+	return records, rowErrors, nil
+}
+
+func (u *residentImportUsecase) validateImportRow(row domain.ResidentImportRow) error {
+	err := u.validator.Validate(row)
+	if err == nil {
+		return nil
+	}
+	var fieldErrors playground.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		messages = append(messages, describeImportFieldError(row, fieldError))
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// describeImportFieldError menjelaskan kesalahan validasi dalam bahasa yang
+// dapat dipahami petugas desa, termasuk alasan NIK atau KK ditolak.
+func describeImportFieldError(row domain.ResidentImportRow, fieldError playground.FieldError) string {
+	field := importFieldName(fieldError.Field())
+	switch fieldError.Tag() {
+	case "required":
+		return field + " is required"
+	case "required_with":
+		return field + " is required when kk_number is filled"
+	case "oneof":
+		return field + " must be one of: " + fieldError.Param()
+	case "max":
+		return field + " must be at most " + fieldError.Param() + " characters"
+	case "numeric":
+		return field + " must be numeric"
+	case "nik_format":
+		if _, err := validator.ParseNIKFormat(row.NIK); err != nil {
+			return "nik: " + err.Error()
+		}
+	case "kk":
+		if _, err := validator.ParseKK(row.KKNumber); err != nil {
+			return "kk_number: " + err.Error()
+		}
+	case "nik_sex":
+		if err := validator.CheckNIKSex(row.NIK, row.Sex); err != nil {
+			return "nik: " + err.Error()
+		}
+	case "nik_birth_date":
+		if err := validator.CheckNIKBirthDate(row.NIK, row.BirthDate); err != nil {
+			return "nik: " + err.Error()
+		}
+	}
+	return field + " is invalid"
+}
+
+func importFieldName(field string) string {
+	switch field {
+	case "NIK":
+		return "nik"
+	case "KKNumber":
+		return "kk_number"
+	case "RT":
+		return "rt"
+	case "RW":
+		return "rw"
+	}
+	var name strings.Builder
+	for i, r := range field {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			name.WriteByte('_')
+		}
+		name.WriteRune(r)
+	}
+	return strings.ToLower(name.String())
+}
+
+func (u *residentImportUsecase) WriteReport(format string, result domain.ImportResult, w io.Writer) error {
+	header := []string{"row", "nik", "name", "error"}
+	rows := make([][]string, 0, len(result.Errors))
+	for _, rowError := range result.Errors {
+		rows = append(rows, []string{strconv.Itoa(rowError.Row), rowError.NIK, rowError.Name, rowError.Message})
+	}
+	return spreadsheet.Write(format, w, header, rows)
+}
+
+// importColumnIndex memetakan nama kolom pada header ke posisinya. Nama
+// kolom tidak peka huruf besar dan spasi dianggap garis bawah.
+func importColumnIndex(header []string) (map[string]int, error) {
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		columns[strings.ReplaceAll(name, " ", "_")] = i
+	}
+	missing := []string{}
+	for _, name := range importRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errors.New("missing column: " + strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+func parseImportRow(number int, line []string, columns map[string]int) (domain.ResidentImportRow, error) {
+	value := func(name string) string {
+		index, ok := columns[name]
+		if !ok || index >= len(line) {
+			return ""
+		}
+		return strings.TrimSpace(line[index])
+	}
+	code := func(name string) string {
+		return strings.ReplaceAll(strings.ToLower(value(name)), " ", "_")
+	}
+
+	row := domain.ResidentImportRow{
+		Row:           number,
+		NIK:           strings.ReplaceAll(value("nik"), "'", ""),
+		Name:          value("name"),
+		BirthPlace:    value("birth_place"),
+		Sex:           code("sex"),
+		Religion:      code("religion"),
+		MaritalStatus: code("marital_status"),
+		Education:     code("education"),
+		Occupation:    value("occupation"),
+		BloodType:     strings.ToUpper(value("blood_type")),
+		FatherName:    value("father_name"),
+		MotherName:    value("mother_name"),
+		RegionCode:    value("region_code"),
+		KKNumber:      strings.ReplaceAll(value("kk_number"), "'", ""),
+		Relationship:  code("relationship"),
+		Address:       value("address"),
+		RT:            value("rt"),
+		RW:            value("rw"),
+	}
+	if sex, ok := importSexValues[row.Sex]; ok {
+		row.Sex = sex
+	}
+	switch row.BloodType {
+	case "-":
+		row.BloodType = ""
+	case "UNKNOWN", "TIDAK TAHU", "TIDAK_TAHU":
+		row.BloodType = domain.BloodTypeUnknown
+	}
+
+	birthDate, err := spreadsheet.ParseDate(value("birth_date"))
+	if err != nil {
+		return row, errors.New("birth_date: " + err.Error())
+	}
+	row.BirthDate = dateOnly(birthDate)
+	return row, nil
+}
+
+func isBlankLine(line []string) bool {
+	for _, value := range line {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
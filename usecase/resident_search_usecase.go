@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+	"github.com/koropati/population-recap/internal/balinese"
+)
+
+const (
+	defaultResidentSearchLimit = 20
+	// residentSearchCandidates adalah kelipatan limit yang diambil dari
+	// database sebelum diberi peringkat ulang dengan nama Bali.
+	residentSearchCandidates = 5
+	// minResidentSearchScore membuang calon yang hanya mirip sedikit.
+	minResidentSearchScore = 0.3
+	// minFulltextTermLength mengikuti innodb_ft_min_token_size bawaan MySQL.
+	minFulltextTermLength = 3
+	// addressSearchWeight membuat kecocokan alamat selalu di bawah
+	// kecocokan nama yang baik.
+	addressSearchWeight = 0.6
+)
+
+type residentSearchUsecase struct {
+	residentSearchRepository domain.ResidentSearchRepository
+	contextTimeout           time.Duration
+}
+
+func NewResidentSearchUsecase(residentSearchRepository domain.ResidentSearchRepository, timeout time.Duration) domain.ResidentSearchUsecase {
+	return &residentSearchUsecase{
+		residentSearchRepository: residentSearchRepository,
+		contextTimeout:           timeout,
+	}
+}
+
+// Search mencari penduduk dengan awalan NIK/KK bila query hanya berisi
+// angka, selain itu dengan nama dan alamat. Hasil diberi peringkat dengan
+// nama yang dinormalisasi sehingga gelar diabaikan, singkatan seperti "Wyn"
+// dikenali dan nama urutan kelahiran yang setara dianggap sama.
+func (u *residentSearchUsecase) Search(c context.Context, query domain.ResidentSearchQuery) (results []domain.ResidentSearchResult, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultResidentSearchLimit
+	}
+
+	if number := strings.ReplaceAll(strings.TrimSpace(query.Query), " ", ""); isDigits(number) {
+		results, err = u.residentSearchRepository.SearchNumber(ctx, number, limit)
+		if err != nil {
+			return nil, err
+		}
+		for i := range results {
+			results[i].Score = 0.9
+			if results[i].NIK == number || results[i].KKNumber == number {
+				results[i].Score = 1
+			}
+		}
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+		return results, nil
+	}
+
+	candidates := limit * residentSearchCandidates
+	byName, err := u.residentSearchRepository.SearchName(ctx, nameSearchTerms(query.Query), candidates)
+	if err != nil {
+		return nil, err
+	}
+	addressTerms := fulltextTerms(balinese.Tokens(query.Query))
+	byAddress, err := u.residentSearchRepository.SearchAddress(ctx, addressTerms, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, result := range append(byName, byAddress...) {
+		if seen[result.ID] {
+			continue
+		}
+		seen[result.ID] = true
+
+		result.Score = balinese.Score(query.Query, result.Name)
+		if score := addressScore(addressTerms, result.Address); score > result.Score {
+			result.Score = score
+		}
+		if result.Score >= minResidentSearchScore {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Name < results[j].Name
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// nameSearchTerms mengambil nama inti sebagai kata kunci FULLTEXT. Gelar
+// tidak dicari karena dimiliki hampir semua penduduk, sedangkan urutan
+// kelahiran hanya dicari bila tidak ada nama inti, beserta nama lain yang
+// setara urutannya.
+func nameSearchTerms(query string) []string {
+	name := balinese.Parse(query)
+	if len(name.Core) > 0 {
+		return fulltextTerms(name.Core)
+	}
+	return fulltextTerms(balinese.BirthOrderNames(name.BirthOrder))
+}
+
+// fulltextTerms membuang kata yang terlalu pendek untuk indeks FULLTEXT.
+func fulltextTerms(tokens []string) []string {
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if len([]rune(token)) >= minFulltextTermLength {
+			terms = append(terms, token)
+		}
+	}
+	return terms
+}
+
+// addressScore adalah porsi kata yang ditemukan pada alamat.
+func addressScore(terms []string, address string) float64 {
+	if len(terms) == 0 || address == "" {
+		return 0
+	}
+	address = strings.ToLower(address)
+	var found int
+	for _, term := range terms {
+		if strings.Contains(address, term) {
+			found++
+		}
+	}
+	return addressSearchWeight * float64(found) / float64(len(terms))
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+)
+
+type residentUsecase struct {
+	residentRepository domain.ResidentRepository
+	regionRepository   domain.RegionRepository
+	contextTimeout     time.Duration
+}
+
+func NewResidentUsecase(residentRepository domain.ResidentRepository, regionRepository domain.RegionRepository, timeout time.Duration) domain.ResidentUsecase {
+	return &residentUsecase{
+		residentRepository: residentRepository,
+		regionRepository:   regionRepository,
+		contextTimeout:     timeout,
+	}
+}
+
+func (u *residentUsecase) Create(c context.Context, resident domain.Resident) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err = checkResidentialRegion(ctx, u.regionRepository, resident.RegionID); err != nil {
+		return err
+	}
+
+	if resident.ID == uuid.Nil {
+		resident.ID, err = uuid.NewUUID()
+		if err != nil {
+			return err
+		}
+	}
+	resident.Status = domain.ResidentStatusActive
+	resident.DeathDate = nil
+	return u.residentRepository.Create(ctx, resident)
+}
+
+func (u *residentUsecase) Retrieve(c context.Context, filter domain.Filter) (residents []domain.Resident, meta domain.MetaResponse, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.residentRepository.Retrieve(ctx, filter)
+}
+
+func (u *residentUsecase) GetById(c context.Context, id uuid.UUID) (resident domain.Resident, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.residentRepository.GetById(ctx, id)
+}
+
+func (u *residentUsecase) GetByNIK(c context.Context, nik string) (resident domain.Resident, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.residentRepository.GetByNIK(ctx, nik)
+}
+
+func (u *residentUsecase) GetPeriods(c context.Context, residentID uuid.UUID) (periods []domain.ResidencePeriod, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.residentRepository.GetPeriods(ctx, residentID)
+}
+
+func (u *residentUsecase) Update(c context.Context, id uuid.UUID, data domain.Resident) (domain.Resident, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if data.RegionID != uuid.Nil {
+		if err := checkResidentialRegion(ctx, u.regionRepository, data.RegionID); err != nil {
+			return domain.Resident{}, err
+		}
+	}
+	return u.residentRepository.Update(ctx, id, data)
+}
+
+func (u *residentUsecase) Delete(c context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.residentRepository.Delete(ctx, id)
+}
+
+func (u *residentUsecase) Restore(c context.Context, id uuid.UUID) (domain.Resident, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := u.residentRepository.Restore(ctx, id); err != nil {
+		return domain.Resident{}, err
+	}
+	return u.residentRepository.GetById(ctx, id)
+}
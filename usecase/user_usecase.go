@@ -44,12 +44,58 @@ func (u *userUsecase) UpdatePassword(c context.Context, id uuid.UUID, newPasswor
 	return u.userRepository.UpdatePassword(ctx, id, newPasswordHash)
 }
 
+func (u *userUsecase) VerifyEmail(c context.Context, id uuid.UUID) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.userRepository.VerifyEmail(ctx, id)
+}
+
+func (u *userUsecase) UpdateStatus(c context.Context, id uuid.UUID, isActive bool) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.userRepository.UpdateStatus(ctx, id, isActive)
+}
+
+func (u *userUsecase) UpdateRole(c context.Context, id uuid.UUID, role string) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.userRepository.UpdateRole(ctx, id, role)
+}
+
+func (u *userUsecase) GetRegions(c context.Context, id uuid.UUID) (regions []domain.Region, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.userRepository.GetRegions(ctx, id)
+}
+
+func (u *userUsecase) GetAssignments(c context.Context, ids []uuid.UUID) (assignments []domain.UserRegion, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.userRepository.GetAssignments(ctx, ids)
+}
+
+func (u *userUsecase) SetRegions(c context.Context, id uuid.UUID, regionIDs []uuid.UUID) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.userRepository.SetRegions(ctx, id, regionIDs)
+}
+
 func (u *userUsecase) Delete(c context.Context, id uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
 	defer cancel()
 	return u.userRepository.Delete(ctx, id)
 }
 
+func (u *userUsecase) Restore(c context.Context, id uuid.UUID) (domain.User, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := u.userRepository.Restore(ctx, id); err != nil {
+		return domain.User{}, err
+	}
+	return u.userRepository.GetById(ctx, id)
+}
+
 func (u *userUsecase) GetByEmail(c context.Context, email string) (user domain.User, err error) {
 	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
 	defer cancel()
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koropati/population-recap/domain"
+)
+
+type verificationEmailTokenUsecase struct {
+	verificationEmailTokenRepository domain.VerificationEmailTokenRepository
+	contextTimeout                   time.Duration
+}
+
+func NewVerificationEmailTokenUsecase(verificationEmailTokenRepository domain.VerificationEmailTokenRepository, timeout time.Duration) domain.VerificationEmailTokenUsecase {
+	return &verificationEmailTokenUsecase{
+		verificationEmailTokenRepository: verificationEmailTokenRepository,
+		contextTimeout:                   timeout,
+	}
+}
+
+func (a *verificationEmailTokenUsecase) Create(c context.Context, verificationEmailToken domain.VerificationEmailToken) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.verificationEmailTokenRepository.Create(ctx, verificationEmailToken)
+}
+
+func (a *verificationEmailTokenUsecase) Revoke(c context.Context, token string) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.verificationEmailTokenRepository.Revoke(ctx, token)
+}
+
+func (a *verificationEmailTokenUsecase) RevokeByUserID(c context.Context, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.verificationEmailTokenRepository.RevokeByUserID(ctx, userID)
+}
+
+func (a *verificationEmailTokenUsecase) IsValid(c context.Context, token string) bool {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.verificationEmailTokenRepository.IsValid(ctx, token)
+}
+
+func (a *verificationEmailTokenUsecase) Delete(c context.Context, token string) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.verificationEmailTokenRepository.Delete(ctx, token)
+}
+
+func (a *verificationEmailTokenUsecase) GetUserID(c context.Context, token string) (userID uuid.UUID, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.verificationEmailTokenRepository.GetUserID(ctx, token)
+}
+
+func (a *verificationEmailTokenUsecase) DeleteExpiredToken(c context.Context, millisDateTime int64) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.verificationEmailTokenRepository.DeleteExpiredToken(ctx, millisDateTime)
+}